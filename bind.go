@@ -0,0 +1,74 @@
+package typemap
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/eko/gocache/lib/v4/cache"
+)
+
+// MustBind bind Impl to Iface with name, if error then panic
+func MustBind[Iface, Impl any](name string, opts ...TypeOption) {
+	err := Bind[Iface, Impl](name, opts...)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Bind register a constructor(see `NewConstructor`) of *Impl with name, and register type *Impl,
+// then the first `Get[Iface](ctx, name)` will construct the *Impl instance and cache it, NOTE:
+// - *Impl should implement Iface, and Iface should be an interface
+// - the constructor is registered as an instance of type `func() Iface`(not Iface), see `Get[func() Iface]`
+// - Iface is registered with `WithBindings` and opts if not registered, otherwise it should be registered with `WithBindings`
+// - the lazy construction is supported by the default instances cache of Iface(see `NewDefaultCache`) only
+func Bind[Iface, Impl any](name string, opts ...TypeOption) error {
+	ifaceType := TypeOf[Iface]()
+	if ifaceType.Kind() != reflect.Interface {
+		return fmt.Errorf("typemap: bind %s failed: not an interface", TypeId{ifaceType})
+	}
+	if !reflect.PointerTo(TypeOf[Impl]()).Implements(ifaceType) {
+		return &TypeMismatchError{TypeId: TypeId{ifaceType}.String(), Key: name, Value: new(Impl)}
+	}
+	if GetType[*Impl](opts...) == nil {
+		if err := RegisterType[*Impl](opts...); err != nil {
+			return err
+		}
+	}
+	if typ := GetType[Iface](opts...); typ == nil {
+		if err := RegisterType[Iface](append(opts[:len(opts):len(opts)], WithBindings())...); err != nil {
+			return err
+		}
+	} else if !typ.bindings {
+		return fmt.Errorf("typemap: bind %s failed: registered without WithBindings", TypeId{ifaceType})
+	}
+	return Register[func() Iface](context.Background(), name, NewConstructor[Impl, Iface](), WithTypeOption(WithTypeMapName(NewTypeOptions(opts...).TypeMapName)))
+}
+
+// Implementations returns all registered types whose values implement Iface in the TypeMap(specified by `WithTypeMapName`),
+// in the order of type id
+func Implementations[Iface any](opts ...TypeOption) []*Type {
+	ifaceType := TypeOf[Iface]()
+	if ifaceType.Kind() != reflect.Interface {
+		return nil
+	}
+	var types []*Type
+	for _, typ := range sortedTypes(opts...) {
+		if typ.typeId != ifaceType && typ.typeId.Implements(ifaceType) {
+			types = append(types, typ)
+		}
+	}
+	return types
+}
+
+// bindingLoader returns a loader of interface T, which constructs the instance by the `func() T` instance bound by `Bind`
+func bindingLoader[T any](typeMapName string) cache.LoadFunction[T] {
+	constructorTypeId := TypeId{reflect.TypeOf((func() T)(nil))}.String() // NOTE: `Get[func() T]` causes instantiation cycle
+	return func(ctx context.Context, key any) (T, error) {
+		constructor, err := GetAny(ctx, constructorTypeId, key, WithTypeOption(WithTypeMapName(typeMapName)))
+		if err != nil {
+			return Zero[T](), err
+		}
+		return constructor.(func() T)(), nil
+	}
+}
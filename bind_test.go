@@ -0,0 +1,84 @@
+package typemap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type EnglishGreeter struct{}
+
+func (EnglishGreeter) Greet() string { return "hello" }
+
+type ChineseGreeter struct{ count int }
+
+func (g *ChineseGreeter) Greet() string {
+	g.count++
+	return "你好"
+}
+
+func TestBind(t *testing.T) {
+	ctx := context.Background()
+	tm := typemap.WithTypeMapName("bind")
+	err := typemap.Bind[Greeter, EnglishGreeter]("en", tm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.Bind[Greeter, ChineseGreeter]("zh", tm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.Bind[Greeter, Impl]("impl", tm)
+	if !typemap.IsTypeMismatch(err) {
+		t.Fatalf("should be type mismatch, got %v", err)
+	}
+	err = typemap.Bind[Impl, Impl]("impl", tm)
+	if err == nil {
+		t.Fatal("should error for non interface")
+	}
+	opt := typemap.WithTypeOption(tm)
+	g, err := typemap.Get[Greeter](ctx, "en", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Greet() != "hello" {
+		t.Fatal("should ==")
+	}
+	g1, err := typemap.Get[Greeter](ctx, "zh", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g1.Greet()
+	g2, err := typemap.Get[Greeter](ctx, "zh", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g2.(*ChineseGreeter).count != 1 {
+		t.Fatal("bound instance should be constructed once")
+	}
+	_, err = typemap.Get[Greeter](ctx, "fr", opt)
+	if !typemap.IsNotFound(err) {
+		t.Fatalf("should be not found, got %v", err)
+	}
+	types := typemap.Implementations[Greeter](tm)
+	if len(types) != 2 || types[0].TypeId() != typemap.TypeOf[*ChineseGreeter]() || types[1].TypeId() != typemap.TypeOf[*EnglishGreeter]() {
+		t.Fatalf("should list 2 implementations, got %v", types)
+	}
+	if c := typemap.GetType[Greeter](tm).InstancesCache(""); c.(interface{ GetType() string }).GetType() != typemap.LoadableSetterCacheAnyType {
+		t.Fatalf("bound interface should use loadable cache, got %T", c)
+	}
+	tm2 := typemap.WithTypeMapName("unbound")
+	typemap.MustRegisterType[Greeter](tm2)
+	if c := typemap.GetType[Greeter](tm2).InstancesCache(""); c.(interface{ GetType() string }).GetType() != typemap.CacheAnyType {
+		t.Fatalf("unbound interface should use plain cache, got %T", c)
+	}
+	err = typemap.Bind[Greeter, EnglishGreeter]("en", tm2)
+	if err == nil {
+		t.Fatal("should failed since registered without WithBindings")
+	}
+}
@@ -2,6 +2,8 @@ package typemap
 
 import (
 	"context"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/eko/gocache/lib/v4/cache"
@@ -15,35 +17,60 @@ const (
 
 	// LoadableSetterCacheAnyType represents the loadable setter cache any type as a string value
 	LoadableSetterCacheAnyType = "loadable_setter_any"
+
+	// ReflectCacheType represents the reflect cache type as a string value
+	ReflectCacheType = "reflect_cache"
 )
 
-// NewDefaultCache create a new default cache for type T
-// - if T implements `Loadable`, returns a `cache.NewLoadable` with Load as LoadFunction
-// - if T implements `DefaultLoader`, returns a `cache.NewLoadable` with LoadDefault as LoadFunction
-// - if T implements `Default`, returns a `cache.NewLoadable` with Default as LoadFunction
-// - if Container() != nil && EnableDI, then use the `Container.Invoke`
-// - otherwise, return a `cache.New`
+// NewDefaultCache create a new default cache for type T, whose load function chains the loaders in order:
+// - loaders specified by `WithLoaders`
+// - if `WithBindings` specified, the constructor bound by `Bind`
+// - if T implements `Loadable`, Load, any error of which is taken as not found if DI enabled(the same as before chaining)
+// - if T implements `DefaultLoader`, LoadDefault, any error of which is taken as not found if DI enabled
+// - if Container() != nil && EnableDI, the `Container.Invoke`, any error of which is taken as not found
+// - if T implements `Default`, Default
+// each loader is skipped if returns a not found error(see `ChainLoaders`), if no loaders then return a `cache.New`.
+// the store of cache is `NewMap()` by default, which can be specified by `WithNewStore`,
+// the load function is wrapped with timeout, retry and negative cache if specified by `WithLoadTimeout`,
+// `WithLoadRetry` and `WithNegativeCache`, if `WithRefresh` specified, the loadable cache will reload the instances populated by loaders periodically
 func NewDefaultCache[T any](opts ...TypeOption) cache.SetterCacheInterface[T] {
-	var sci cache.SetterCacheInterface[T]
+	options := NewTypeOptions(opts...)
+	newStore := options.NewStore
+	if newStore == nil {
+		newStore = func() store.StoreInterface { return NewMap() }
+	}
+	loaders, _ := options.Loaders.([]cache.LoadFunction[T])
+	if options.Bindings && TypeOf[T]().Kind() == reflect.Interface {
+		loaders = append(loaders, bindingLoader[T](options.TypeMapName))
+	}
 	var value any = Zero[T]()
-	switch t := value.(type) {
-	case Loadable[T]:
-		sci = NewLoadable[T](t.Load, cache.New[T](NewMap()))
-	case DefaultLoader[T]:
-		sci = NewLoadable[T](t.LoadDefault, cache.New[T](NewMap()))
-	case Default[T]:
-		loader := func(ctx context.Context, key any) (T, error) {
+	enableDI := Container() != nil && options.EnableDI
+	if t, ok := value.(Loadable[T]); ok {
+		loaders = append(loaders, fallthroughOnError(t.Load, enableDI))
+	}
+	if t, ok := value.(DefaultLoader[T]); ok {
+		loaders = append(loaders, fallthroughOnError(t.LoadDefault, enableDI))
+	}
+	if enableDI {
+		loaders = append(loaders, notFoundOnError(LoadFuncOfDAG[T](Container())))
+	}
+	if t, ok := value.(Default[T]); ok {
+		loaders = append(loaders, func(ctx context.Context, key any) (T, error) {
 			return t.Default(), nil
-		}
-		sci = NewLoadable[T](loader, cache.New[T](NewMap()))
-	default:
-		sci = NewCacheAny[T](NewMap())
+		})
 	}
-	options := NewTypeOptions(opts...)
-	if Container() != nil && options.EnableDI {
-		sci = NewLoadable[T](LoadFuncOfDAG[T](Container()), sci)
+	if len(loaders) == 0 {
+		return NewCacheAny[T](newStore())
 	}
-	return sci
+	loadable := NewLoadable[T](resilientLoader(ChainLoaders(loaders...), options), cache.New[T](newStore()))
+	if options.RefreshInterval > 0 {
+		ctx := options.RefreshContext
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		loadable.StartRefresh(ctx, options.RefreshInterval)
+	}
+	return loadable
 }
 
 // CacheAny represents a setter cache and implements SetterAnyCacheInterface
@@ -73,25 +100,105 @@ func (c *CacheAny[T]) GetAnyWithTTL(ctx context.Context, key any) (any, time.Dur
 	return c.GetWithTTL(ctx, key)
 }
 
+// SetAny set the key and value to object(any), returns `*TypeMismatchError` if object is not a T
 func (c *CacheAny[T]) SetAny(ctx context.Context, key any, object any, options ...store.Option) error {
-	return c.Set(ctx, key, object.(T), options...)
+	value, ok := object.(T)
+	if !ok {
+		return &TypeMismatchError{TypeId: TypeIdOf[T]().String(), Key: key, Value: object}
+	}
+	return c.Set(ctx, key, value, options...)
 }
 
 // LoadableSetterCacheAny represents a setter cache that uses a function to load data, and implements `SetterAnyCacheInterface`
 type LoadableSetterCacheAny[T any] struct {
 	setterCache cache.SetterCacheInterface[T]
 	*cache.LoadableCache[T]
+	loadFunc  cache.LoadFunction[T]
+	loaded    map[any]struct{} // keys populated by loadFunc, which are reloaded by `Refresh`
+	loadedMu  sync.Mutex
+	stop      chan struct{}
+	refreshWg sync.WaitGroup
+	startOnce sync.Once
+	stopOnce  sync.Once
+	closeOnce sync.Once
 }
 
-// NewLoadable instanciates a new setter cache that uses a function to load data
+// NewLoadable instanciates a new setter cache that uses a function to load data,
+// concurrent loads of the same key are deduplicated, the loaded value is set into setterCache before shared to waiters
 func NewLoadable[T any](loadFunc cache.LoadFunction[T], setterCache cache.SetterCacheInterface[T]) *LoadableSetterCacheAny[T] {
 	loadable := &LoadableSetterCacheAny[T]{
-		setterCache:   setterCache,
-		LoadableCache: cache.NewLoadable(loadFunc, cache.CacheInterface[T](setterCache)),
+		setterCache: setterCache,
+		loadFunc:    loadFunc,
+		stop:        make(chan struct{}),
+	}
+	group := &flightGroup[T]{}
+	load := func(ctx context.Context, key any) (T, error) {
+		return group.do(ctx, key, func(ctx context.Context) (T, error) {
+			value, err := loadFunc(ctx, key)
+			if err != nil {
+				return value, err
+			}
+			loadable.markLoaded(key)
+			_ = setterCache.Set(ctx, key, value) // NOTE: avoid loading again before `LoadableCache` sets asynchronously
+			return value, nil
+		})
 	}
+	loadable.LoadableCache = cache.NewLoadable(load, cache.CacheInterface[T](setterCache))
 	return loadable
 }
 
+// Set populates the cache item using the given key, the key will not be reloaded by `Refresh` any more
+func (c *LoadableSetterCacheAny[T]) Set(ctx context.Context, key any, object T, options ...store.Option) error {
+	c.forget(key)
+	return c.LoadableCache.Set(ctx, key, object, options...)
+}
+
+// Delete removes the cache item using the given key
+func (c *LoadableSetterCacheAny[T]) Delete(ctx context.Context, key any) error {
+	c.forget(key)
+	return c.LoadableCache.Delete(ctx, key)
+}
+
+// Clear resets all cache data
+func (c *LoadableSetterCacheAny[T]) Clear(ctx context.Context) error {
+	c.loadedMu.Lock()
+	c.loaded = nil
+	c.loadedMu.Unlock()
+	return c.LoadableCache.Clear(ctx)
+}
+
+// markLoaded records key populated by loadFunc, non-comparable keys are not recorded and never refreshed
+func (c *LoadableSetterCacheAny[T]) markLoaded(key any) {
+	if !isComparableKey(key) {
+		return
+	}
+	c.loadedMu.Lock()
+	defer c.loadedMu.Unlock()
+	if c.loaded == nil {
+		c.loaded = make(map[any]struct{})
+	}
+	c.loaded[key] = struct{}{}
+}
+
+func (c *LoadableSetterCacheAny[T]) forget(key any) {
+	if !isComparableKey(key) {
+		return
+	}
+	c.loadedMu.Lock()
+	defer c.loadedMu.Unlock()
+	delete(c.loaded, key)
+}
+
+func (c *LoadableSetterCacheAny[T]) loadedKeys() []any {
+	c.loadedMu.Lock()
+	defer c.loadedMu.Unlock()
+	keys := make([]any, 0, len(c.loaded))
+	for key := range c.loaded {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 func (c *LoadableSetterCacheAny[T]) GetWithTTL(ctx context.Context, key any) (T, time.Duration, error) {
 	return c.setterCache.GetWithTTL(ctx, key)
 }
@@ -116,7 +223,144 @@ func (c *LoadableSetterCacheAny[T]) GetAnyWithTTL(ctx context.Context, key any)
 	return c.GetWithTTL(ctx, key)
 }
 
-// SetAny set the key and value to object(any)
+// SetAny set the key and value to object(any), returns `*TypeMismatchError` if object is not a T
 func (c *LoadableSetterCacheAny[T]) SetAny(ctx context.Context, key any, object any, options ...store.Option) error {
-	return c.Set(ctx, key, object.(T), options...)
+	value, ok := object.(T)
+	if !ok {
+		return &TypeMismatchError{TypeId: TypeIdOf[T]().String(), Key: key, Value: object}
+	}
+	return c.Set(ctx, key, value, options...)
+}
+
+// Refresh reload the cached instances populated by the load function, instances set explicitly by `Set` are kept,
+// instances failed to reload keep the cached ones, and the failures are returned as a `*MultiError`
+func (c *LoadableSetterCacheAny[T]) Refresh(ctx context.Context) error {
+	merr := &MultiError{}
+	for _, key := range c.loadedKeys() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := c.reload(ctx, key); err != nil {
+			merr.add(key, err)
+		}
+	}
+	return merr.errorOrNil()
 }
+
+// reload reload the instance of key, the reloaded value is discarded if the key is set or deleted meanwhile
+func (c *LoadableSetterCacheAny[T]) reload(ctx context.Context, key any) error {
+	value, err := c.loadFunc(ctx, key)
+	if err != nil {
+		return err
+	}
+	c.loadedMu.Lock()
+	defer c.loadedMu.Unlock()
+	if _, ok := c.loaded[key]; !ok {
+		return nil
+	}
+	return c.setterCache.Set(ctx, key, value)
+}
+
+// StartRefresh start a goroutine which calls `Refresh` every interval until ctx done or `Close` called,
+// if `Refresh` failed, the interval will be doubled until `maxRefreshBackoff` times of interval, and reset after a success.
+// NOTE: only the first call takes effect
+func (c *LoadableSetterCacheAny[T]) StartRefresh(ctx context.Context, interval time.Duration) {
+	c.startOnce.Do(func() {
+		c.refreshWg.Add(1)
+		go c.refreshLoop(ctx, interval)
+	})
+}
+
+func (c *LoadableSetterCacheAny[T]) refreshLoop(ctx context.Context, interval time.Duration) {
+	defer c.refreshWg.Done()
+	delay := interval
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.stop:
+			return
+		case <-timer.C:
+			if err := c.Refresh(ctx); err != nil {
+				delay *= 2
+				if delay > interval*maxRefreshBackoff {
+					delay = interval * maxRefreshBackoff
+				}
+			} else {
+				delay = interval
+			}
+			timer.Reset(delay)
+		}
+	}
+}
+
+// Close stop the background refresh and close the underlying `cache.LoadableCache`,
+// the cache should not be used after Close
+func (c *LoadableSetterCacheAny[T]) Close() error {
+	c.closeOnce.Do(func() {
+		c.stopRefresh()
+		c.refreshWg.Wait()
+		_ = c.LoadableCache.Close()
+	})
+	return nil
+}
+
+// stopRefresh signal the background refresh to stop without waiting, the cache is still usable,
+// e.g. replaced by `SetType` but still referenced
+func (c *LoadableSetterCacheAny[T]) stopRefresh() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+// maxRefreshBackoff the max multiple of refresh interval when refresh failed continuously
+const maxRefreshBackoff = 16
+
+var _ Refreshable = (*LoadableSetterCacheAny[any])(nil)
+
+// ReflectCache represents a setter cache of instances of a reflect.Type, and implements `SetterCacheAnyInterface`,
+// which is the default instances cache of `RegisterReflectType`
+type ReflectCache struct {
+	typ reflect.Type
+	*cache.Cache[any]
+}
+
+// NewReflectCache instantiates a new cache of typ's instances
+func NewReflectCache(typ reflect.Type, store store.StoreInterface) *ReflectCache {
+	return &ReflectCache{
+		typ:   typ,
+		Cache: cache.New[any](store),
+	}
+}
+
+// ReflectType returns the type of instances
+func (c *ReflectCache) ReflectType() reflect.Type {
+	return c.typ
+}
+
+// GetType returns the cache type
+func (c *ReflectCache) GetType() string {
+	return ReflectCacheType
+}
+
+// GetAny returns the object stored in cache if it exists
+func (c *ReflectCache) GetAny(ctx context.Context, key any) (any, error) {
+	return c.Get(ctx, key)
+}
+
+// GetAnyWithTTL returns the object stored in cache and its corresponding TTL
+func (c *ReflectCache) GetAnyWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
+	return c.GetWithTTL(ctx, key)
+}
+
+// SetAny set the key and value to object(any), returns `*TypeMismatchError` if object is not assignable to the type
+func (c *ReflectCache) SetAny(ctx context.Context, key any, object any, options ...store.Option) error {
+	if object == nil || !reflect.TypeOf(object).AssignableTo(c.typ) {
+		return &TypeMismatchError{TypeId: TypeId{c.typ}.String(), Key: key, Value: object}
+	}
+	return c.Set(ctx, key, object, options...)
+}
+
+var _ SetterCacheAnyInterface = (*ReflectCache)(nil)
@@ -3,9 +3,12 @@ package typemap_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -182,3 +185,187 @@ func TestNewDefaultCacheContainer(t *testing.T) {
 	}
 	logger.Print("You've been invoked hahaah")
 }
+
+var singleflightLoads int32
+
+type SingleflightType struct {
+	Value string
+}
+
+func (SingleflightType) Load(ctx context.Context, key any) (*SingleflightType, error) {
+	atomic.AddInt32(&singleflightLoads, 1)
+	time.Sleep(50 * time.Millisecond)
+	return &SingleflightType{Value: key.(string)}, nil
+}
+
+func TestLoadableSingleflight(t *testing.T) {
+	err := typemap.RegisterType[*SingleflightType]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	canceledCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := context.Background()
+			if i == 0 {
+				ctx = canceledCtx
+			}
+			v, err := typemap.Get[*SingleflightType](ctx, "shared")
+			if err == nil && v.Value != "shared" {
+				err = fmt.Errorf("should == shared, got %s", v.Value)
+			}
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+	if !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("canceled waiter should got deadline exceeded, got %v", errs[0])
+	}
+	for i, err := range errs[1:] {
+		if err != nil {
+			t.Errorf("waiter %d got error: %v", i+1, err)
+		}
+	}
+	if n := atomic.LoadInt32(&singleflightLoads); n != 1 {
+		t.Fatalf("load should run once, got %d", n)
+	}
+	_, err = typemap.Get[*SingleflightType](context.Background(), "shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&singleflightLoads); n != 1 {
+		t.Fatalf("loaded value should be cached, got %d loads", n)
+	}
+}
+
+var refreshVersion int32
+
+type RefreshType struct {
+	Version int32
+}
+
+func (RefreshType) Load(ctx context.Context, key any) (*RefreshType, error) {
+	return &RefreshType{Version: atomic.AddInt32(&refreshVersion, 1)}, nil
+}
+
+func TestLoadableRefresh(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := typemap.RegisterType[*RefreshType](typemap.WithRefresh(10*time.Millisecond), typemap.WithRefreshContext(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := typemap.Get[*RefreshType](context.Background(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := v.Version
+	deadline := time.Now().Add(time.Second)
+	for {
+		v, err = typemap.Get[*RefreshType](context.Background(), "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Version > first {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("cached instance should be refreshed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	r, ok := typemap.GetType[*RefreshType]().InstancesCache("").(typemap.Refreshable)
+	if !ok {
+		t.Fatal("instances cache should be refreshable")
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	stopped := atomic.LoadInt32(&refreshVersion)
+	time.Sleep(30 * time.Millisecond)
+	if n := atomic.LoadInt32(&refreshVersion); n != stopped {
+		t.Fatalf("refresh should stop after close, got %d loads, want %d", n, stopped)
+	}
+}
+
+type RefreshDefaultType struct {
+	Value string
+}
+
+func (RefreshDefaultType) Default() *RefreshDefaultType {
+	return &RefreshDefaultType{Value: "default"}
+}
+
+func TestLoadableRefreshKeepSet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	typemap.MustRegisterType[*RefreshDefaultType](typemap.WithRefresh(5*time.Millisecond), typemap.WithRefreshContext(ctx))
+	typemap.MustSet(ctx, "mine", &RefreshDefaultType{Value: "mine"})
+	v, err := typemap.Get[*RefreshDefaultType](ctx, "loaded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Value != "default" {
+		t.Fatal("should ==")
+	}
+	time.Sleep(30 * time.Millisecond)
+	v, err = typemap.Get[*RefreshDefaultType](ctx, "mine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Value != "mine" {
+		t.Fatalf("instance set explicitly should not be refreshed, got %s", v.Value)
+	}
+}
+
+var refreshReplaceVersion int32
+
+type RefreshReplaceType struct{}
+
+func (RefreshReplaceType) Load(ctx context.Context, key any) (*RefreshReplaceType, error) {
+	atomic.AddInt32(&refreshReplaceVersion, 1)
+	return &RefreshReplaceType{}, nil
+}
+
+func TestLoadableRefreshReplaced(t *testing.T) {
+	ctx := context.Background()
+	typemap.MustRegisterType[*RefreshReplaceType](typemap.WithRefresh(5 * time.Millisecond))
+	_, err := typemap.Get[*RefreshReplaceType](ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if atomic.LoadInt32(&refreshReplaceVersion) < 2 {
+		t.Fatal("should be refreshed")
+	}
+	typemap.MustSetType[*RefreshReplaceType]()
+	time.Sleep(10 * time.Millisecond) // NOTE: wait the in-flight refresh
+	stopped := atomic.LoadInt32(&refreshReplaceVersion)
+	time.Sleep(30 * time.Millisecond)
+	if n := atomic.LoadInt32(&refreshReplaceVersion); n != stopped {
+		t.Fatalf("refresh of replaced cache should stop, got %d loads, want %d", n, stopped)
+	}
+}
+
+type NonComparableKeyType struct{}
+
+func (NonComparableKeyType) Default() *NonComparableKeyType {
+	return &NonComparableKeyType{}
+}
+
+func TestLoadableNonComparableKey(t *testing.T) {
+	typemap.MustRegisterType[*NonComparableKeyType](typemap.WithNegativeCache(time.Second))
+	key := struct{ V any }{V: []int{1}}
+	v, err := typemap.Get[*NonComparableKeyType](context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v == nil {
+		t.Fatal("should load default")
+	}
+}
@@ -0,0 +1,90 @@
+package typemap
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// ConversionPolicy the policy used by SetAny|RegisterAny to convert an object to T, each policy includes the previous ones,
+// converters registered by `RegisterConverter` are always used before reflect conversions
+type ConversionPolicy int
+
+const (
+	// StrictConversion only accepts objects assignable to T
+	StrictConversion ConversionPolicy = iota
+
+	// ReflectConversion also accepts objects convertible to T by reflect, except integer to string
+	ReflectConversion
+
+	// JSONConversion also accepts objects which can be marshaled to JSON and then unmarshaled to T
+	JSONConversion
+)
+
+// convert convert value to instance of typ according to policy, returns `*TypeMismatchError` if failed
+func (typ *Type) convert(tag string, key any, value any, policy ConversionPolicy) (any, error) {
+	mismatch := &TypeMismatchError{TypeId: typ.String(), Tag: tag, Key: key, Value: value}
+	t := typ.typeId
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return reflect.Zero(t).Interface(), nil
+		}
+		return nil, mismatch
+	}
+	v := reflect.ValueOf(value)
+	if v.Type() == t {
+		return value, nil
+	}
+	if v.Type().AssignableTo(t) {
+		return v.Convert(t).Interface(), nil
+	}
+	if c, ok := lookupConverter(v.Type(), t); ok {
+		converted, err := c(value)
+		if err != nil {
+			mismatch.Err = err
+			return nil, mismatch
+		}
+		return converted, nil
+	}
+	if policy >= ReflectConversion && convertible(v.Type(), t) {
+		if converted, ok := convertValue(v, t); ok {
+			return converted, nil
+		}
+	}
+	if policy >= JSONConversion {
+		data, err := json.Marshal(value)
+		if err != nil {
+			mismatch.Err = err
+			return nil, mismatch
+		}
+		n := typ.New()
+		if err = json.Unmarshal(data, n); err != nil {
+			mismatch.Err = err
+			return nil, mismatch
+		}
+		return typ.Deref(n), nil
+	}
+	return nil, mismatch
+}
+
+// convertible reports whether from can be converted to to, integer to string is excluded since it yields a rune
+func convertible(from, to reflect.Type) bool {
+	if to.Kind() == reflect.String {
+		switch from.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return false
+		}
+	}
+	return from.ConvertibleTo(to)
+}
+
+// convertValue convert v to t, returns false if panic, e.g. slice to array with a short length
+func convertValue(v reflect.Value, t reflect.Type) (converted any, ok bool) {
+	defer func() {
+		if recover() != nil {
+			converted, ok = nil, false
+		}
+	}()
+	return v.Convert(t).Interface(), true
+}
@@ -0,0 +1,120 @@
+package typemap
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// RegisterConverter register a converter from From to To, which is used when the stored type differs from the requested one:
+// - SetAny|RegisterAny: convert the object to T before reflect conversions(see `ConversionPolicy`)
+// - Ref[To]: resolve the From instance with the same name if To's instance not found
+// - RefAttr[T, To]: convert the attr value of From to To
+// NOTE:
+// - register the same From and To again will override the previous converter
+// - if From is an interface, the converter is also used for types implementing From, unless they have their own converter
+// - converters are global rather than per TypeMap, since a conversion depends only on From and To, not on where instances are stored
+func RegisterConverter[From, To any](fn func(From) (To, error)) {
+	key := converterKey{from: TypeIdOf[From](), to: TypeIdOf[To]()}
+	globalConverters.lock.Lock()
+	defer globalConverters.lock.Unlock()
+	if globalConverters.converters == nil {
+		globalConverters.converters = make(map[converterKey]converter)
+	}
+	globalConverters.converters[key] = func(v any) (any, error) {
+		return fn(v.(From))
+	}
+}
+
+type converterKey struct {
+	from TypeId
+	to   TypeId
+}
+
+type converter func(v any) (any, error)
+
+var globalConverters struct {
+	converters map[converterKey]converter
+	lock       sync.RWMutex
+}
+
+// lookupConverter returns the converter from from to to, if not found then returns the converter from an interface
+// which from implements, in the order of type id
+func lookupConverter(from, to reflect.Type) (converter, bool) {
+	globalConverters.lock.RLock()
+	defer globalConverters.lock.RUnlock()
+	if c, ok := globalConverters.converters[converterKey{from: TypeId{from}, to: TypeId{to}}]; ok {
+		return c, true
+	}
+	var match *converterKey
+	for key := range globalConverters.converters {
+		if key.to.Type != to || key.from.Kind() != reflect.Interface || !from.Implements(key.from.Type) {
+			continue
+		}
+		if match == nil || key.from.String() < match.from.String() {
+			key := key
+			match = &key
+		}
+	}
+	if match == nil {
+		return nil, false
+	}
+	return globalConverters.converters[*match], true
+}
+
+// convertersTo returns the source types of converters to to, in the order of type id
+func convertersTo(to reflect.Type) []reflect.Type {
+	globalConverters.lock.RLock()
+	var froms []reflect.Type
+	for key := range globalConverters.converters {
+		if key.to.Type == to {
+			froms = append(froms, key.from.Type)
+		}
+	}
+	globalConverters.lock.RUnlock()
+	sort.Slice(froms, func(i, j int) bool {
+		return TypeId{froms[i]}.String() < TypeId{froms[j]}.String()
+	})
+	return froms
+}
+
+// convertTo convert v to T, use v itself if it is a T, otherwise use the registered converter
+func convertTo[T any](v any) (T, error) {
+	if tv, ok := v.(T); ok {
+		return tv, nil
+	}
+	if v != nil {
+		if c, ok := lookupConverter(reflect.TypeOf(v), TypeOf[T]()); ok {
+			cv, err := c(v)
+			if err != nil {
+				return Zero[T](), &TypeMismatchError{TypeId: TypeIdOf[T]().String(), Value: v, Err: err}
+			}
+			if cv == nil { // NOTE: converter to interface may returns nil
+				return Zero[T](), nil
+			}
+			tv, ok := cv.(T)
+			if !ok {
+				return Zero[T](), &TypeMismatchError{TypeId: TypeIdOf[T]().String(), Value: cv}
+			}
+			return tv, nil
+		}
+	}
+	return Zero[T](), &TypeMismatchError{TypeId: TypeIdOf[T]().String(), Value: v}
+}
+
+// getConverted get T's instance, if not found then try to get instances of types which can be converted to T
+func getConverted[T any](ctx context.Context, key any, opts ...Option) (T, error) {
+	v, err := Get[T](ctx, key, opts...)
+	if err == nil || !IsNotFound(err) {
+		return v, err
+	}
+	for _, from := range convertersTo(TypeOf[T]()) {
+		fv, ferr := GetAny(ctx, TypeId{from}.String(), key, opts...)
+		if ferr != nil {
+			continue
+		}
+		return convertTo[T](fv)
+	}
+	return v, err
+}
@@ -2,6 +2,9 @@ package typemap
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/eko/gocache/lib/v4/store"
 )
@@ -28,3 +31,189 @@ func IsNotFound(err error) bool {
 	var e *NotFoundError
 	return errors.As(err, &e) || errors.Is(err, store.NotFound{})
 }
+
+// KeyError the error occurred on a key
+type KeyError struct {
+	Key any
+	Err error
+}
+
+// Error implements the error interface.
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Key, e.Err)
+}
+
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError collects errors of multiple keys
+type MultiError struct {
+	Errors []*KeyError
+}
+
+// Error implements the error interface.
+func (e *MultiError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, ke := range e.Errors {
+		msgs = append(msgs, ke.Error())
+	}
+	return fmt.Sprintf("typemap: %d errors occurred: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Is reports whether any error matches target, NOTE: errors.Is does not unwrap `Unwrap() []error` before go1.20
+func (e *MultiError) Is(target error) bool {
+	for _, ke := range e.Errors {
+		if errors.Is(ke, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error that matches target, NOTE: errors.As does not unwrap `Unwrap() []error` before go1.20
+func (e *MultiError) As(target any) bool {
+	for _, ke := range e.Errors {
+		if errors.As(ke, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, ke := range e.Errors {
+		errs = append(errs, ke)
+	}
+	return errs
+}
+
+// Keys returns keys of all errors
+func (e *MultiError) Keys() []any {
+	keys := make([]any, 0, len(e.Errors))
+	for _, ke := range e.Errors {
+		keys = append(keys, ke.Key)
+	}
+	return keys
+}
+
+func (e *MultiError) add(key any, err error) {
+	e.Errors = append(e.Errors, &KeyError{Key: key, Err: err})
+}
+
+// errorOrNil returns nil if no errors collected
+func (e *MultiError) errorOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// AlreadyExistsError the tag cache or instance already exists, Key is nil for tag cache
+type AlreadyExistsError struct {
+	TypeId string
+	Tag    string
+	Key    any
+}
+
+// Error implements the error interface.
+func (e *AlreadyExistsError) Error() string {
+	return "typemap: " + describe(e.TypeId, e.Tag, e.Key) + " already exists"
+}
+
+// IsAlreadyExists reports whether err is an `*AlreadyExistsError`
+func IsAlreadyExists(err error) bool {
+	var e *AlreadyExistsError
+	return errors.As(err, &e)
+}
+
+// TypeMismatchError the value is not an instance of the type, Err is the cause of the failed conversion if any
+type TypeMismatchError struct {
+	TypeId string
+	Tag    string
+	Key    any
+	Value  any
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *TypeMismatchError) Error() string {
+	msg := fmt.Sprintf("typemap: %s value type mismatch: %T", describe(e.TypeId, e.Tag, e.Key), e.Value)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *TypeMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// IsTypeMismatch reports whether err is a `*TypeMismatchError`
+func IsTypeMismatch(err error) bool {
+	var e *TypeMismatchError
+	return errors.As(err, &e)
+}
+
+// CacheTypeError the instances cache of tag is not a cache of the type
+type CacheTypeError struct {
+	TypeId string
+	Tag    string
+	Cache  any
+}
+
+// Error implements the error interface.
+func (e *CacheTypeError) Error() string {
+	return fmt.Sprintf("typemap: %s invalid instances cache type: %T", describe(e.TypeId, e.Tag, nil), e.Cache)
+}
+
+// IsCacheType reports whether err is a `*CacheTypeError`
+func IsCacheType(err error) bool {
+	var e *CacheTypeError
+	return errors.As(err, &e)
+}
+
+// TypeIdConflictError different types with the same type id string or alias(see `WithAlias`)
+type TypeIdConflictError struct {
+	TypeId   string
+	Exists   reflect.Type
+	Conflict reflect.Type
+}
+
+// Error implements the error interface.
+func (e *TypeIdConflictError) Error() string {
+	return fmt.Sprintf("typemap: type id %s conflicts: %v(%s) and %v(%s)",
+		e.TypeId, e.Exists, e.Exists.PkgPath(), e.Conflict, e.Conflict.PkgPath())
+}
+
+// IsTypeIdConflict reports whether err is a `*TypeIdConflictError`
+func IsTypeIdConflict(err error) bool {
+	var e *TypeIdConflictError
+	return errors.As(err, &e)
+}
+
+// describe describes the subject of errors, e.g. `type *pkg.Demo tag x key k`, empty parts are omitted
+func describe(typeId, tag string, key any) string {
+	parts := make([]string, 0, 3)
+	if typeId != "" {
+		parts = append(parts, "type "+typeId)
+	}
+	if tag != "" {
+		parts = append(parts, "tag "+tag)
+	}
+	if key != nil {
+		parts = append(parts, fmt.Sprintf("key %v", key))
+	}
+	return strings.Join(parts, " ")
+}
+
+// withSubject fill the empty type id and tag of err returned by stores, which know nothing about them
+func withSubject(err error, typeId, tag string) error {
+	var ae *AlreadyExistsError
+	if errors.As(err, &ae) && ae.TypeId == "" {
+		ae.TypeId = typeId
+		ae.Tag = tag
+	}
+	return err
+}
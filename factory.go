@@ -0,0 +1,115 @@
+package typemap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Factory constructs an instance of T from raw config
+type Factory[T any] func(ctx context.Context, config json.RawMessage) (T, error)
+
+// factory is the type erased `Factory[T]`
+type factory func(ctx context.Context, config json.RawMessage) (any, error)
+
+// MustRegisterFactory register a named factory of T, panic if failed
+func MustRegisterFactory[T any](name string, fn Factory[T], opts ...TypeOption) {
+	err := RegisterFactory(name, fn, opts...)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegisterFactory register a named factory of T, T must be registered first(see `RegisterType`),
+// the factory names are listed in `Type.MarshalJSON`, e.g.
+//
+// typemap.RegisterFactory("redis", func(ctx context.Context, config json.RawMessage) (Cache, error) {...})
+// cache, err := typemap.Build[Cache](ctx, "redis", []byte(`{"addr": "..."}`))
+func RegisterFactory[T any](name string, fn Factory[T], opts ...TypeOption) error {
+	if fn == nil {
+		return fmt.Errorf("register factory %s of %s failed: nil factory", name, TypeIdOf[T]())
+	}
+	typ := GetType[T](opts...)
+	if typ == nil {
+		return NewNotFoundError(fmt.Sprintf("register factory %s: type %s not registered", name, TypeIdOf[T]()))
+	}
+	return typ.registerFactory(name, func(ctx context.Context, config json.RawMessage) (any, error) {
+		return fn(ctx, config)
+	})
+}
+
+// Build constructs an instance of T by the factory registered with name and raw config
+func Build[T any](ctx context.Context, name string, config json.RawMessage, opts ...TypeOption) (T, error) {
+	typ := GetType[T](opts...)
+	if typ == nil {
+		return Zero[T](), NewNotFoundError(fmt.Sprintf("build %s: type %s not registered", name, TypeIdOf[T]()))
+	}
+	v, err := typ.build(ctx, name, config)
+	if err != nil {
+		return Zero[T](), err
+	}
+	if v == nil { // NOTE: factory of interface type may returns nil
+		return Zero[T](), nil
+	}
+	tv, ok := v.(T)
+	if !ok {
+		return Zero[T](), &TypeMismatchError{TypeId: typ.String(), Key: name, Value: v}
+	}
+	return tv, nil
+}
+
+// BuildAny constructs an instance of type specified by typeIdStr(or alias) by the factory registered with name and raw config
+func BuildAny(ctx context.Context, typeIdStr, name string, config json.RawMessage, opts ...TypeOption) (any, error) {
+	typ := GetTypeByID(typeIdStr, opts...)
+	if typ == nil {
+		return nil, NewNotFoundError(fmt.Sprintf("build %s: type %s not registered", name, typeIdStr))
+	}
+	return typ.build(ctx, name, config)
+}
+
+// Factories returns the names of factories registered by `RegisterFactory` in order
+func (typ *Type) Factories() []string {
+	typ.lock.RLock()
+	defer typ.lock.RUnlock()
+	return typ.factoryNames()
+}
+
+func (typ *Type) factoryNames() []string {
+	if len(typ.factories) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(typ.factories))
+	for name := range typ.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (typ *Type) registerFactory(name string, fn factory) error {
+	typ.lock.Lock()
+	defer typ.lock.Unlock()
+	if _, ok := typ.factories[name]; ok {
+		return &AlreadyExistsError{TypeId: typ.String(), Key: "factory " + name}
+	}
+	if typ.factories == nil {
+		typ.factories = make(map[string]factory)
+	}
+	typ.factories[name] = fn
+	return nil
+}
+
+func (typ *Type) build(ctx context.Context, name string, config json.RawMessage) (any, error) {
+	typ.lock.RLock()
+	fn, ok := typ.factories[name]
+	typ.lock.RUnlock()
+	if !ok {
+		return nil, NewNotFoundError(fmt.Sprintf("factory %s of %s not found", name, typ.String()))
+	}
+	v, err := fn(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("build %s by factory %s failed: %w", typ.String(), name, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,82 @@
+package typemap_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+type FactoryTest struct {
+	Addr string `json:"addr"`
+}
+
+func TestFactory(t *testing.T) {
+	ctx := context.Background()
+	fn := func(ctx context.Context, config json.RawMessage) (*FactoryTest, error) {
+		ft := &FactoryTest{}
+		err := json.Unmarshal(config, ft)
+		return ft, err
+	}
+	err := typemap.RegisterFactory("redis", fn)
+	if !typemap.IsNotFound(err) {
+		t.Fatalf("should be not found, got %v", err)
+	}
+	typemap.MustRegisterType[*FactoryTest](typemap.WithDescription("factory test"))
+	typemap.MustRegisterFactory("redis", fn)
+	typemap.MustRegisterFactory("failed", func(ctx context.Context, config json.RawMessage) (*FactoryTest, error) {
+		return nil, errors.New("boom")
+	})
+	err = typemap.RegisterFactory("redis", fn)
+	if !typemap.IsAlreadyExists(err) {
+		t.Fatalf("should be already exists, got %v", err)
+	}
+	ft, err := typemap.Build[*FactoryTest](ctx, "redis", json.RawMessage(`{"addr": "localhost"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft.Addr != "localhost" {
+		t.Fatal("should ==")
+	}
+	v, err := typemap.BuildAny(ctx, typemap.TypeIdOf[*FactoryTest]().String(), "redis", json.RawMessage(`{"addr": "remote"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(*FactoryTest).Addr != "remote" {
+		t.Fatal("should ==")
+	}
+	_, err = typemap.Build[*FactoryTest](ctx, "not-exist", nil)
+	if !typemap.IsNotFound(err) {
+		t.Fatalf("should be not found, got %v", err)
+	}
+	_, err = typemap.Build[*FactoryTest](ctx, "failed", nil)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("should failed with boom, got %v", err)
+	}
+	typ := typemap.GetType[*FactoryTest]()
+	if names := typ.Factories(); len(names) != 2 || names[0] != "failed" || names[1] != "redis" {
+		t.Fatalf("should ==, got %v", names)
+	}
+	b, err := json.Marshal(typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"factories":["failed","redis"]`) {
+		t.Fatalf("should contains factories, got %s", b)
+	}
+	typemap.MustRegisterType[FactoryIface]()
+	typemap.MustRegisterFactory("nil", func(ctx context.Context, config json.RawMessage) (FactoryIface, error) {
+		return nil, nil
+	})
+	fi, err := typemap.Build[FactoryIface](ctx, "nil", nil)
+	if err != nil || fi != nil {
+		t.Fatalf("should got nil, got %v, %v", fi, err)
+	}
+}
+
+type FactoryIface interface {
+	Addr() string
+}
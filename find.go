@@ -0,0 +1,61 @@
+package typemap
+
+import (
+	"context"
+	"reflect"
+)
+
+// Find returns instances of T which satisfy filter in a stable key order
+func Find[T any](ctx context.Context, filter func(key any, v T) bool, opts ...Option) ([]Entry[T], error) {
+	var entries []Entry[T]
+	err := Range(ctx, func(key any, v T) bool {
+		if filter(key, v) {
+			entries = append(entries, Entry[T]{Key: key, Value: v})
+		}
+		return true
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindAny returns instances of T(specified by typeIdStr) which satisfy filter in a stable key order
+func FindAny(ctx context.Context, typeIdStr string, filter func(key any, v any) bool, opts ...Option) ([]Entry[any], error) {
+	options := NewOptions(opts...)
+	typ, cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := instanceKeys(ctx, typ, options.Tag, cache)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry[any]
+	for _, key := range keys {
+		v, err := storeGet(ctx, cache, key)
+		if err != nil {
+			if IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if filter(key, v) {
+			entries = append(entries, Entry[any]{Key: key, Value: v})
+		}
+	}
+	return entries, nil
+}
+
+// Where returns a filter used by `Find` or `FindAny`(use `Where[any]`), which matches instances whose attr equals to value,
+// attr is got by `GetAttr`(nested struct field attr are connected by `.`), instances without attr will not match.
+// e.g. `typemap.Find[*Config](ctx, typemap.Where[*Config]("Region", "eu"))`
+func Where[T any](attr string, value any) func(key any, v T) bool {
+	return func(_ any, v T) bool {
+		av, err := GetAttr(v, attr)
+		if err != nil {
+			return false
+		}
+		return reflect.DeepEqual(av, value)
+	}
+}
@@ -0,0 +1,65 @@
+package typemap_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+type FindTest struct {
+	Region string
+	Meta   struct {
+		Zone int
+	}
+}
+
+func TestFind(t *testing.T) {
+	ctx := context.Background()
+	for key, region := range map[string]string{"d": "eu", "a": "eu", "c": "us", "b": "eu"} {
+		v := &FindTest{Region: region}
+		v.Meta.Zone = int(key[0] - 'a')
+		err := typemap.Register(ctx, key, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := typemap.Find(ctx, typemap.Where[*FindTest]("Region", "eu"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[0].Key != "a" || entries[1].Key != "b" || entries[2].Key != "d" {
+		t.Fatalf("find got %v", entries)
+	}
+	entries, err = typemap.Find(ctx, typemap.Where[*FindTest]("Meta.Zone", 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Value.Region != "us" {
+		t.Fatalf("find nested got %v", entries)
+	}
+	entries, err = typemap.Find(ctx, typemap.Where[*FindTest]("NotExist", 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("find not exist attr should got nothing, got %v", entries)
+	}
+	anyEntries, err := typemap.FindAny(ctx, typemap.TypeIdOf[*FindTest]().String(), func(key any, v any) bool {
+		return strings.Compare(key.(string), "b") >= 0 && v.(*FindTest).Region == "eu"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anyEntries) != 2 || anyEntries[0].Key != "b" || anyEntries[1].Key != "d" {
+		t.Fatalf("find any got %v", anyEntries)
+	}
+	anyEntries, err = typemap.FindAny(ctx, typemap.TypeIdOf[*FindTest]().String(), typemap.Where[any]("Region", "us"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anyEntries) != 1 || anyEntries[0].Key != "c" {
+		t.Fatalf("find any where got %v", anyEntries)
+	}
+}
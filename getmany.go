@@ -0,0 +1,103 @@
+package typemap
+
+import (
+	"context"
+	"sync"
+
+	"github.com/eko/gocache/lib/v4/store"
+)
+
+// getMany get values of keys, use store's `GetManyInterface` first if implemented, then get the rest by get
+func getMany[T any](ctx context.Context, s store.StoreInterface, keys []any, get func(ctx context.Context, key any) (T, error), options *Options) ([]T, error) {
+	values := make([]T, len(keys))
+	errs := make([]error, len(keys))
+	pending := make([]int, 0, len(keys))
+	found := make([]bool, len(keys))
+	if gm, ok := s.(GetManyInterface); ok {
+		var strKeys []any
+		for _, key := range keys {
+			if _, ok := key.(string); ok { // NOTE: other keys are hashed by cache, so can not get from store directly
+				strKeys = append(strKeys, key)
+			}
+		}
+		if len(strKeys) > 0 {
+			if m, err := gm.GetMany(ctx, strKeys); err == nil {
+				for i, key := range keys {
+					if v, ok := m[key]; ok {
+						if tv, ok := v.(T); ok {
+							values[i] = tv
+							found[i] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	for i := range keys {
+		if !found[i] {
+			pending = append(pending, i)
+		}
+	}
+	if options.Concurrency <= 1 {
+		for _, i := range pending {
+			values[i], errs[i] = get(ctx, keys[i])
+			if errs[i] != nil && !options.PartialResults {
+				return nil, errs[i]
+			}
+		}
+	} else {
+		var firstErr error
+		var once sync.Once
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		indexes := make(chan int)
+		var wg sync.WaitGroup
+		workers := options.Concurrency
+		if workers > len(pending) {
+			workers = len(pending)
+		}
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range indexes {
+					values[i], errs[i] = get(ctx, keys[i])
+					if errs[i] != nil && !options.PartialResults {
+						once.Do(func() {
+							firstErr = errs[i]
+							cancel()
+						})
+					}
+				}
+			}()
+		}
+		sent := 0
+	loop:
+		for _, i := range pending {
+			select {
+			case indexes <- i:
+				sent++
+			case <-ctx.Done():
+				break loop
+			}
+		}
+		close(indexes)
+		wg.Wait()
+		for _, i := range pending[sent:] {
+			errs[i] = ctx.Err()
+		}
+		if firstErr != nil {
+			return nil, firstErr
+		}
+		if err := ctx.Err(); err != nil && !options.PartialResults {
+			return nil, err
+		}
+	}
+	me := &MultiError{}
+	for i, err := range errs {
+		if err != nil {
+			me.add(keys[i], err)
+		}
+	}
+	return values, me.errorOrNil()
+}
@@ -0,0 +1,110 @@
+package typemap_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ccmonky/typemap"
+)
+
+var (
+	getManyRunning int32
+	getManyMax     int32
+)
+
+type GetManyTest struct {
+	Key string
+}
+
+func (GetManyTest) LoadDefault(ctx context.Context, key any) (*GetManyTest, error) {
+	running := atomic.AddInt32(&getManyRunning, 1)
+	defer atomic.AddInt32(&getManyRunning, -1)
+	for {
+		max := atomic.LoadInt32(&getManyMax)
+		if running <= max || atomic.CompareAndSwapInt32(&getManyMax, max, running) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if key.(string)[0] == 'x' {
+		return nil, fmt.Errorf("load %v failed: bad key", key)
+	}
+	return &GetManyTest{Key: key.(string)}, nil
+}
+
+func TestGetManyConcurrency(t *testing.T) {
+	ctx := context.Background()
+	err := typemap.Set(ctx, "stored", &GetManyTest{Key: "from-store"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []any{"stored", "a", "b", "c", "d", "e", "f"}
+	start := time.Now()
+	values, err := typemap.GetMany[*GetManyTest](ctx, keys, typemap.WithConcurrency(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) >= 60*time.Millisecond {
+		t.Errorf("should fetch concurrently, cost %v", time.Since(start))
+	}
+	if max := atomic.LoadInt32(&getManyMax); max > 3 {
+		t.Errorf("concurrency should <= 3, got %d", max)
+	}
+	if values[0].Key != "from-store" {
+		t.Errorf("stored value should be got from store, got %s", values[0].Key)
+	}
+	for i, key := range keys[1:] {
+		if values[i+1].Key != key {
+			t.Errorf("value %d should == %v, got %v", i+1, key, values[i+1].Key)
+		}
+	}
+}
+
+func TestGetManyPartialResults(t *testing.T) {
+	ctx := context.Background()
+	keys := []any{"a", "x1", "b", "x2"}
+	for _, concurrency := range []int{0, 2} {
+		values, err := typemap.GetMany[*GetManyTest](ctx, keys,
+			typemap.WithConcurrency(concurrency), typemap.WithPartialResults())
+		var me *typemap.MultiError
+		if !errors.As(err, &me) {
+			t.Fatalf("should be *MultiError, got %v", err)
+		}
+		if len(me.Errors) != 2 || me.Keys()[0] != "x1" || me.Keys()[1] != "x2" {
+			t.Fatalf("should fail on x1 & x2, got %v", err)
+		}
+		if len(values) != 4 || values[0].Key != "a" || values[1] != nil || values[2].Key != "b" || values[3] != nil {
+			t.Fatalf("partial values got %v", values)
+		}
+		_, err = typemap.GetMany[*GetManyTest](ctx, keys, typemap.WithConcurrency(concurrency))
+		if err == nil || errors.As(err, &me) {
+			t.Fatalf("should fail fast with the first error, got %v", err)
+		}
+	}
+	anyValues, err := typemap.GetAnyMany(ctx, typemap.TypeIdOf[*GetManyTest]().String(), keys,
+		typemap.WithConcurrency(4), typemap.WithPartialResults())
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if anyValues[2].(*GetManyTest).Key != "b" {
+		t.Fatalf("any values got %v", anyValues)
+	}
+}
+
+func TestMultiErrorIsAs(t *testing.T) {
+	me := &typemap.MultiError{Errors: []*typemap.KeyError{
+		{Key: "a", Err: errors.New("other")},
+		{Key: "b", Err: fmt.Errorf("wrapped: %w", typemap.NewNotFoundError("b not found"))},
+	}}
+	var nf *typemap.NotFoundError
+	if !me.As(&nf) || !me.Is(nf) || me.Is(errors.New("other")) {
+		t.Fatal("should match the wrapped errors")
+	}
+	if !typemap.IsNotFound(me) {
+		t.Fatal("should be not found")
+	}
+}
@@ -2,7 +2,11 @@ module github.com/ccmonky/typemap
 
 go 1.18
 
-require github.com/eko/gocache/lib/v4 v4.1.2
+require (
+	github.com/eko/gocache/lib/v4 v4.1.2
+	github.com/stretchr/testify v1.8.1
+	go.uber.org/dig v1.16.1
+)
 
 require (
 	github.com/beorn7/perks v1.0.1 // indirect
@@ -16,8 +20,6 @@ require (
 	github.com/prometheus/client_model v0.3.0 // indirect
 	github.com/prometheus/common v0.37.0 // indirect
 	github.com/prometheus/procfs v0.8.0 // indirect
-	github.com/stretchr/testify v1.8.1 // indirect
-	go.uber.org/dig v1.16.1 // indirect
 	golang.org/x/exp v0.0.0-20221126150942-6ab00d035af9 // indirect
 	golang.org/x/sys v0.2.0 // indirect
 	google.golang.org/protobuf v1.28.1 // indirect
@@ -18,6 +18,7 @@ func init() {
 	MustRegister[http.HandlerFunc](context.Background(), "POST:/typemap/instances/getter", GetAPI)
 	MustRegister[http.HandlerFunc](context.Background(), "POST:/typemap/instances/setter", SetAPI)
 	MustRegister[http.HandlerFunc](context.Background(), "POST:/typemap/instances/deletion", DeleteAPI)
+	MustRegister[http.HandlerFunc](context.Background(), "POST:/typemap/instances/list", ListAPI)
 }
 
 func TypesListAPI(w http.ResponseWriter, r *http.Request) {
@@ -44,7 +45,7 @@ func SetAPI(w http.ResponseWriter, r *http.Request) {
 	var instances []Instance
 	err = json.Unmarshal(data, &instances)
 	if err != nil {
-		render(w, http.StatusInternalServerError, "json unmarshal body failed: %v", err)
+		render(w, http.StatusBadRequest, "json unmarshal body failed: %v", err)
 		return
 	}
 	for i, instance := range instances {
@@ -55,25 +56,25 @@ func SetAPI(w http.ResponseWriter, r *http.Request) {
 		if instance.Operation != "register_any" && instance.Operation != "set_any" {
 			instance.Operation = "set_any"
 		}
-		typ := GetTypeByID(instance.TypeID)
+		typ := ResolveTypeByID(instance.TypeID)
 		if typ == nil {
-			render(w, http.StatusInternalServerError, "type %s not registered", instance.TypeID)
+			render(w, http.StatusNotFound, "type %s not registered", instance.TypeID)
 			return
 		}
 		n := typ.New()
 		err = json.Unmarshal(instance.Value, &n)
 		if err != nil {
-			render(w, http.StatusInternalServerError, "type %s not unmarshal value failed: %v", instance.TypeID, err)
+			render(w, http.StatusBadRequest, "type %s not unmarshal value failed: %v", instance.TypeID, err)
 			return
 		}
 		switch instance.Operation {
 		case "register_any":
-			err = RegisterAny(r.Context(), instance.TypeID, instance.Name, typ.Deref(n))
+			err = RegisterAny(r.Context(), typ.String(), instance.Name, typ.Deref(n))
 		default:
-			err = SetAny(r.Context(), instance.TypeID, instance.Name, typ.Deref(n))
+			err = SetAny(r.Context(), typ.String(), instance.Name, typ.Deref(n))
 		}
 		if err != nil {
-			render(w, http.StatusInternalServerError, "type %s %s %s:%v failed: %v",
+			render(w, statusOf(err), "type %s %s %s:%v failed: %v",
 				instance.TypeID, instance.Operation, instance.Name, n, err)
 			return
 		}
@@ -90,7 +91,7 @@ func GetAPI(w http.ResponseWriter, r *http.Request) {
 	var instances []Instance
 	err = json.Unmarshal(data, &instances)
 	if err != nil {
-		render(w, http.StatusInternalServerError, "json unmarshal body failed: %v", err)
+		render(w, http.StatusBadRequest, "json unmarshal body failed: %v", err)
 		return
 	}
 	for i := range instances {
@@ -99,14 +100,14 @@ func GetAPI(w http.ResponseWriter, r *http.Request) {
 			render(w, http.StatusBadRequest, "instance %d type id is empty", i)
 			return
 		}
-		typ := GetTypeByID(instance.TypeID)
+		typ := ResolveTypeByID(instance.TypeID)
 		if typ == nil {
-			render(w, http.StatusBadRequest, "type %s not registered", instance.TypeID)
+			render(w, http.StatusNotFound, "type %s not registered", instance.TypeID)
 			return
 		}
 		value, err := GetAny(r.Context(), typ.String(), instance.Name)
 		if err != nil {
-			render(w, http.StatusInternalServerError, "type %s get %s failed: %v", instance.TypeID, instance.Name, err)
+			render(w, statusOf(err), "type %s get %s failed: %v", instance.TypeID, instance.Name, err)
 			return
 		}
 		data, err := json.Marshal(value)
@@ -134,7 +135,7 @@ func DeleteAPI(w http.ResponseWriter, r *http.Request) {
 	var instances []Instance
 	err = json.Unmarshal(data, &instances)
 	if err != nil {
-		render(w, http.StatusInternalServerError, "json unmarshal body failed: %v", err)
+		render(w, http.StatusBadRequest, "json unmarshal body failed: %v", err)
 		return
 	}
 	for i, instance := range instances {
@@ -142,20 +143,63 @@ func DeleteAPI(w http.ResponseWriter, r *http.Request) {
 			render(w, http.StatusBadRequest, "instance %d type id is empty", i)
 			return
 		}
-		typ := GetTypeByID(instance.TypeID)
+		typ := ResolveTypeByID(instance.TypeID)
 		if typ == nil {
-			render(w, http.StatusInternalServerError, "type %s not registered", instance.TypeID)
+			render(w, http.StatusNotFound, "type %s not registered", instance.TypeID)
 			return
 		}
 		err = DeleteAny(r.Context(), typ.String(), instance.Name)
 		if err != nil {
-			render(w, http.StatusInternalServerError, "type %s delete %s failed: %v", instance.TypeID, instance.Name, err)
+			render(w, statusOf(err), "type %s delete %s failed: %v", instance.TypeID, instance.Name, err)
 			return
 		}
 	}
 	io.WriteString(w, "success")
 }
 
+func ListAPI(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		render(w, http.StatusInternalServerError, "read body failed: %v", err)
+		return
+	}
+	var query ListQuery
+	err = json.Unmarshal(data, &query)
+	if err != nil {
+		render(w, http.StatusBadRequest, "json unmarshal body failed: %v", err)
+		return
+	}
+	if query.TypeID == "" {
+		render(w, http.StatusBadRequest, "type id is empty")
+		return
+	}
+	typ := ResolveTypeByID(query.TypeID)
+	if typ == nil {
+		render(w, http.StatusNotFound, "type %s not registered", query.TypeID)
+		return
+	}
+	page, err := ListAny(r.Context(), typ.String(), query.Cursor, query.Limit)
+	if err != nil {
+		render(w, statusOf(err), "type %s list failed: %v", query.TypeID, err)
+		return
+	}
+	data, err = json.Marshal(page)
+	if err != nil {
+		render(w, http.StatusInternalServerError, "type %s marshal page failed: %v", query.TypeID, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// ListQuery the request body of ListAPI
+type ListQuery struct {
+	TypeID string `json:"type_id"`
+	Cursor string `json:"cursor,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// Instance the request and response body of instances apis, TypeID can be the type id string or alias(see `WithAlias`)
 type Instance struct {
 	TypeID    string          `json:"type_id"`
 	Operation string          `json:"operation,omitempty"`
@@ -163,6 +207,23 @@ type Instance struct {
 	Value     json.RawMessage `json:"value,omitempty"`
 }
 
+// statusOf maps typemap errors to http status code:
+// - not found: 404
+// - already exists, type id conflict: 409
+// - type mismatch, invalid cache type: 400
+// - otherwise: 500
+func statusOf(err error) int {
+	switch {
+	case IsNotFound(err):
+		return http.StatusNotFound
+	case IsAlreadyExists(err), IsTypeIdConflict(err):
+		return http.StatusConflict
+	case IsTypeMismatch(err), IsCacheType(err):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 func render(w http.ResponseWriter, status int, format string, args ...any) {
 	if status != 200 {
 		log.Printf(format, args...)
@@ -193,3 +193,66 @@ func testDeleteAPI(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+type StatusTest struct {
+	Int int `json:"int"`
+}
+
+func TestAPIStatus(t *testing.T) {
+	err := typemap.RegisterType[*StatusTest]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(typemap.SetAPI))
+	defer ts.Close()
+	body := `[{"type_id": "github.com/ccmonky/typemap_test:*typemap_test.StatusTest", "operation": "register_any", "name": "dup", "value": {"int": 1}}]`
+	for i, want := range []int{http.StatusOK, http.StatusConflict} {
+		rp, err := http.Post(ts.URL, "application/json", bytes.NewReader([]byte(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rp.Body.Close()
+		if rp.StatusCode != want {
+			t.Fatalf("request %d should got %d, got %d", i, want, rp.StatusCode)
+		}
+	}
+	rp, err := http.Post(ts.URL, "application/json", bytes.NewReader([]byte(`[{"type_id": "not-exist", "name": "x"}]`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp.Body.Close()
+	if rp.StatusCode != http.StatusNotFound {
+		t.Fatalf("should got 404, got %d", rp.StatusCode)
+	}
+}
+
+type CompositeHandlerTest struct {
+	Int int `json:"int"`
+}
+
+func TestInstancesAPICompositeTypeId(t *testing.T) {
+	err := typemap.RegisterType[[]*CompositeHandlerTest]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeId := "[]*github.com/ccmonky/typemap_test:CompositeHandlerTest"
+	for _, c := range []struct {
+		api  http.HandlerFunc
+		body string
+	}{
+		{typemap.SetAPI, `[{"type_id": "` + typeId + `", "name": "a", "value": [{"int": 1}]}]`},
+		{typemap.GetAPI, `[{"type_id": "` + typeId + `", "name": "a"}]`},
+		{typemap.ListAPI, `{"type_id": "` + typeId + `"}`},
+		{typemap.DeleteAPI, `[{"type_id": "` + typeId + `", "name": "a"}]`},
+	} {
+		rr := httptest.NewRecorder()
+		c.api(rr, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(c.body))))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s should ok, got %d: %s", c.body, rr.Code, rr.Body.String())
+		}
+	}
+	_, err = typemap.Get[[]*CompositeHandlerTest](context.Background(), "a")
+	if !typemap.IsNotFound(err) {
+		t.Fatalf("should deleted, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package typemap
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// InjectTagName is the struct tag name used by `Inject`
+const InjectTagName = "typemap"
+
+// Inject fills the fields of struct target(must be a pointer) tagged with `typemap:"name[,tag=x][,attr=y][,optional]"`
+// with instances stored in typemap, e.g.
+//
+//	type Service struct {
+//		DB      *sql.DB                  `typemap:"main"`
+//		Cache   Cache                    `typemap:"redis,tag=prod,optional"`
+//		Handler Ref[http.HandlerFunc]    `typemap:"trace"`
+//		Timeout RefAttr[*Config, string] `typemap:"app,attr=Timeout"`
+//	}
+//
+// err := typemap.Inject(ctx, &service)
+//
+// - name: the instance name, the field name is used if omitted
+// - tag: the tag of instances cache, override the tag specified by opts
+// - attr: the attr of `RefAttr` field
+// - optional: leave the field untouched if the instance not found
+// - instances are got by the field's type, `Ref` and `RefAttr` fields are referenced by their own type parameters
+// - `Ref` and `RefAttr` fields keep the tag for later `Value`
+// - returns a `*MultiError` keyed by field name listing all unresolved fields
+func Inject(ctx context.Context, target any, opts ...Option) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("inject target should be a non-nil struct pointer, got %T", target)
+	}
+	value = value.Elem()
+	typ := value.Type()
+	me := &MultiError{}
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		spec, ok := sf.Tag.Lookup(InjectTagName)
+		if !ok || spec == "-" {
+			continue
+		}
+		if !sf.IsExported() {
+			me.add(sf.Name, fmt.Errorf("field %s of %s is unexported", sf.Name, typ))
+			continue
+		}
+		it := parseInjectTag(sf.Name, spec)
+		fopts := opts
+		if it.hasTag {
+			fopts = mergeOptions(fopts, []Option{WithTag(it.tag)})
+		}
+		err := injectField(ctx, value.Field(i), it, fopts...)
+		if err != nil {
+			if it.optional && IsNotFound(err) {
+				continue
+			}
+			me.add(sf.Name, err)
+		}
+	}
+	return me.errorOrNil()
+}
+
+// injector implemented by `*Ref[T]` and `*RefAttr[T, A]` to be injected by `Inject`
+type injector interface {
+	inject(ctx context.Context, name, attr string, opts ...Option) error
+}
+
+type injectTag struct {
+	name     string
+	tag      string
+	hasTag   bool
+	attr     string
+	optional bool
+}
+
+func parseInjectTag(fieldName, spec string) injectTag {
+	parts := strings.Split(spec, ",")
+	it := injectTag{name: strings.TrimSpace(parts[0])}
+	if it.name == "" {
+		it.name = fieldName
+	}
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "optional":
+			it.optional = true
+		case strings.HasPrefix(part, "tag="):
+			it.tag, it.hasTag = strings.TrimPrefix(part, "tag="), true
+		case strings.HasPrefix(part, "attr="):
+			it.attr = strings.TrimPrefix(part, "attr=")
+		}
+	}
+	return it
+}
+
+func injectField(ctx context.Context, field reflect.Value, it injectTag, opts ...Option) error {
+	if inj, ok := field.Addr().Interface().(injector); ok {
+		return inj.inject(ctx, it.name, it.attr, opts...)
+	}
+	v, err := GetAny(ctx, TypeId{field.Type()}.String(), it.name, opts...)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.Type().AssignableTo(field.Type()) {
+		return &TypeMismatchError{TypeId: TypeId{field.Type()}.String(), Key: it.name, Value: v}
+	}
+	field.Set(rv)
+	return nil
+}
@@ -0,0 +1,74 @@
+package typemap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+type InjectDB struct {
+	DSN string
+}
+
+type InjectService struct {
+	DB       *InjectDB                          `typemap:"main"`
+	TestDB   *InjectDB                          `typemap:"main,tag=test"`
+	Backup   *InjectDB                          `typemap:"backup,optional"`
+	Ref      typemap.Ref[*InjectDB]             `typemap:"main"`
+	DSN      typemap.RefAttr[*InjectDB, string] `typemap:"main,attr=DSN"`
+	TestRef  typemap.Ref[*InjectDB]             `typemap:"main,tag=test"`
+	TestDSN  typemap.RefAttr[*InjectDB, string] `typemap:"main,tag=test,attr=DSN"`
+	Untagged *InjectDB
+}
+
+type InjectBadService struct {
+	DB      *InjectDB `typemap:"missing"`
+	Timeout int       `typemap:"timeout"`
+	Ignored *InjectDB `typemap:"-"`
+}
+
+func TestInject(t *testing.T) {
+	ctx := context.Background()
+	typemap.MustRegisterType[*InjectDB](typemap.WithInstancesCache[*InjectDB]("", nil), typemap.WithInstancesCache[*InjectDB]("test", nil))
+	typemap.MustRegister(ctx, "main", &InjectDB{DSN: "main"})
+	typemap.MustRegister(ctx, "main", &InjectDB{DSN: "test"}, typemap.WithTag("test"))
+	service := &InjectService{}
+	err := typemap.Inject(ctx, service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.DB.DSN != "main" || service.TestDB.DSN != "test" {
+		t.Fatal("should ==")
+	}
+	if service.Backup != nil || service.Untagged != nil {
+		t.Fatal("should be nil")
+	}
+	if service.Ref.Name != "main" || service.Ref.MustValue(ctx).DSN != "main" {
+		t.Fatal("should ==")
+	}
+	if service.DSN.MustValue(ctx) != "main" {
+		t.Fatal("should ==")
+	}
+	testDB, err := service.TestRef.Value(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if testDB.DSN != "test" || service.TestDSN.MustValue(ctx) != "test" {
+		t.Fatal("should use the tag of struct tag")
+	}
+	bad := &InjectBadService{}
+	err = typemap.Inject(ctx, bad)
+	me, ok := err.(*typemap.MultiError)
+	if !ok {
+		t.Fatalf("should be multi error, got %v", err)
+	}
+	keys := me.Keys()
+	if len(keys) != 2 || keys[0] != "DB" || keys[1] != "Timeout" {
+		t.Fatalf("should ==, got %v", keys)
+	}
+	err = typemap.Inject(ctx, *service)
+	if err == nil {
+		t.Fatal("should failed for non pointer")
+	}
+}
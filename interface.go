@@ -42,6 +42,29 @@ type GetAllInterface interface {
 	GetAll(ctx context.Context) (map[any]any, error)
 }
 
+// KeysInterface used for stores to support enumerating instances without copying the whole store
+type KeysInterface interface {
+	Keys(ctx context.Context) ([]any, error)
+}
+
+// GetManyInterface used for stores to support fetching multiple keys in one call, missing keys should be absent in result
+type GetManyInterface interface {
+	GetMany(ctx context.Context, keys []any) (map[any]any, error)
+}
+
+// PrefixInterface used for ordered stores to support lookups by key prefix, keys should be returned in order
+type PrefixInterface interface {
+	KeysWithPrefix(ctx context.Context, prefix string) ([]any, error)
+}
+
+// Refreshable used for instances caches which can reload their cached instances, e.g. `LoadableSetterCacheAny`
+type Refreshable interface {
+	// Refresh reload all cached instances once
+	Refresh(ctx context.Context) error
+	// Close stop the background refresh
+	Close() error
+}
+
 // SetterCacheAnyInterface
 type SetterCacheAnyInterface interface {
 	GetAny(ctx context.Context, key any) (any, error)
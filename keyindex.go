@@ -0,0 +1,86 @@
+package typemap
+
+import (
+	"context"
+	"sync"
+
+	"github.com/eko/gocache/lib/v4/codec"
+)
+
+// keyIndex the keys of a tag's instances maintained by typemap, used for stores which can not enumerate keys
+type keyIndex struct {
+	keys map[any]struct{}
+	lock sync.RWMutex
+}
+
+func (index *keyIndex) update(op Operation, key any) {
+	index.lock.Lock()
+	defer index.lock.Unlock()
+	switch op {
+	case RegisterOperation, SetOperation:
+		index.keys[key] = struct{}{}
+	case DeleteOperation:
+		delete(index.keys, key)
+	case ClearOperation:
+		index.keys = make(map[any]struct{})
+	}
+}
+
+func (index *keyIndex) list() []any {
+	index.lock.RLock()
+	defer index.lock.RUnlock()
+	keys := make([]any, 0, len(index.keys))
+	for key := range index.keys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// reconcile returns keys which still exist in store, and remove the others(e.g., evicted by the store) from index
+func (index *keyIndex) reconcile(ctx context.Context, cache interface {
+	GetCodec() codec.CodecInterface
+}) ([]any, error) {
+	store := cache.GetCodec().GetStore()
+	keys := index.list()
+	exists := keys[:0]
+	for _, key := range keys {
+		_, err := store.Get(ctx, cacheKey(key))
+		if err != nil {
+			if !IsNotFound(err) {
+				return nil, err
+			}
+			index.lock.Lock()
+			delete(index.keys, key)
+			index.lock.Unlock()
+			continue
+		}
+		exists = append(exists, key)
+	}
+	return exists, nil
+}
+
+// keyIndex returns key index of tag, nil if key index not enabled
+func (typ *Type) keyIndex(tag string) *keyIndex {
+	typ.lock.RLock()
+	if !typ.keyIndexed {
+		typ.lock.RUnlock()
+		return nil
+	}
+	index := typ.keyIndexes[tag]
+	typ.lock.RUnlock()
+	if index != nil {
+		return index
+	}
+	typ.lock.Lock()
+	defer typ.lock.Unlock()
+	if typ.keyIndexes == nil {
+		typ.keyIndexes = make(map[string]*keyIndex)
+	}
+	if index = typ.keyIndexes[tag]; index == nil {
+		index = &keyIndex{
+			keys: make(map[any]struct{}),
+		}
+		typ.keyIndexes[tag] = index
+	}
+	return index
+}
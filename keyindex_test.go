@@ -0,0 +1,199 @@
+package typemap_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ccmonky/typemap"
+	"github.com/eko/gocache/lib/v4/store"
+)
+
+// plainStore a store which can not enumerate its keys, like most third-party gocache stores
+type plainStore struct {
+	items sync.Map
+}
+
+func (s *plainStore) Get(_ context.Context, key any) (any, error) {
+	value, ok := s.items.Load(key)
+	if !ok {
+		return nil, store.NotFoundWithCause(fmt.Errorf("%v not found in plain store", key))
+	}
+	return value, nil
+}
+
+func (s *plainStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
+	value, err := s.Get(ctx, key)
+	return value, typemap.NoExpiration, err
+}
+
+func (s *plainStore) Set(_ context.Context, key any, value any, _ ...store.Option) error {
+	s.items.Store(key, value)
+	return nil
+}
+
+func (s *plainStore) Delete(_ context.Context, key any) error {
+	s.items.Delete(key)
+	return nil
+}
+
+func (s *plainStore) Invalidate(_ context.Context, _ ...store.InvalidateOption) error {
+	return nil
+}
+
+func (s *plainStore) Clear(_ context.Context) error {
+	s.items = sync.Map{}
+	return nil
+}
+
+func (s *plainStore) GetType() string {
+	return "plain"
+}
+
+type KeyIndexTest struct {
+	Value string `json:"value"`
+}
+
+func TestKeyIndex(t *testing.T) {
+	ctx := context.Background()
+	s := &plainStore{}
+	err := typemap.RegisterType[*KeyIndexTest](
+		typemap.WithInstancesCache[*KeyIndexTest]("", typemap.NewCacheAny[*KeyIndexTest](s)),
+		typemap.WithKeyIndex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"b", "a", "c"} {
+		err = typemap.Register(ctx, key, &KeyIndexTest{Value: key})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, err := typemap.GetAll[*KeyIndexTest](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 3 || m["a"].Value != "a" {
+		t.Fatalf("GetAll got %v", m)
+	}
+	err = typemap.Delete[*KeyIndexTest](ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Delete(ctx, "c") // NOTE: simulate evicted by store
+	keys, err := typemap.Keys[*KeyIndexTest](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "a" {
+		t.Fatalf("keys should == [a], got %v", keys)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(typemap.ListAPI))
+	defer ts.Close()
+	rp, err := http.Post(ts.URL, "application/json", bytes.NewReader([]byte(`{
+		"type_id": "github.com/ccmonky/typemap_test:*typemap_test.KeyIndexTest"
+	}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rp.Body.Close()
+	data, err := io.ReadAll(rp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var page typemap.Page
+	err = json.Unmarshal(data, &page)
+	if err != nil {
+		t.Fatalf("unmarshal %s failed: %v", string(data), err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Key != "a" {
+		t.Fatalf("list got %s", string(data))
+	}
+	err = typemap.Clear[*KeyIndexTest](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err = typemap.Keys[*KeyIndexTest](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("keys should be empty after clear, got %v", keys)
+	}
+}
+
+type NoKeyIndexTest struct{}
+
+func TestNoKeyIndex(t *testing.T) {
+	err := typemap.RegisterType[NoKeyIndexTest](
+		typemap.WithInstancesCache[NoKeyIndexTest]("", typemap.NewCacheAny[NoKeyIndexTest](&plainStore{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = typemap.GetAll[NoKeyIndexTest](context.Background())
+	if err == nil {
+		t.Fatal("should error if key index not enabled")
+	}
+}
+
+type KeyIndexIntTest struct{}
+
+func TestKeyIndexNonStringKey(t *testing.T) {
+	ctx := context.Background()
+	err := typemap.RegisterType[*KeyIndexIntTest](
+		typemap.WithInstancesCache[*KeyIndexIntTest]("", typemap.NewCacheAny[*KeyIndexIntTest](&plainStore{})),
+		typemap.WithKeyIndex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []int{2, 1} {
+		err = typemap.Set(ctx, key, &KeyIndexIntTest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	keys, err := typemap.Keys[*KeyIndexIntTest](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0] != 1 || keys[1] != 2 {
+		t.Fatalf("non-string keys should be listed, got %v", keys)
+	}
+	m, err := typemap.GetAll[*KeyIndexIntTest](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 2 || m[1] == nil {
+		t.Fatalf("non-string keys should be got, got %v", m)
+	}
+	err = typemap.Set(ctx, "1", &KeyIndexIntTest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeId := typemap.TypeIdOf[*KeyIndexIntTest]().String()
+	keys = nil
+	var cursor string
+	for {
+		page, err := typemap.ListAny(ctx, typeId, cursor, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range page.Items {
+			keys = append(keys, item.Key)
+		}
+		if page.Next == "" {
+			break
+		}
+		cursor = page.Next
+	}
+	if len(keys) != 3 || keys[0] != 1 || keys[1] != "1" || keys[2] != 2 {
+		t.Fatalf("keys with the same string form should all be listed, got %v", keys)
+	}
+}
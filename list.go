@@ -0,0 +1,253 @@
+package typemap
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/eko/gocache/lib/v4/cache"
+	"github.com/eko/gocache/lib/v4/codec"
+	"github.com/eko/gocache/lib/v4/store"
+)
+
+// Entry a key and instance pair of T
+type Entry[T any] struct {
+	Key   any `json:"key"`
+	Value T   `json:"value"`
+}
+
+// Page a page of instances returned by `ListAny`
+type Page struct {
+	Items []Entry[any] `json:"items"`
+	// Next is the cursor used to get next page, empty if no more instances
+	Next string `json:"next,omitempty"`
+}
+
+// Keys returns all keys of T's instances in a stable order
+func Keys[T any](ctx context.Context, opts ...Option) ([]any, error) {
+	options := NewOptions(opts...)
+	typ, cache, err := getInstancesCache[T](options.Tag, false, options.TypeOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return instanceKeys(ctx, typ, options.Tag, cache)
+}
+
+// KeysAny returns all keys of T(specified by typeIdStr)'s instances in a stable order
+func KeysAny(ctx context.Context, typeIdStr string, opts ...Option) ([]any, error) {
+	options := NewOptions(opts...)
+	typ, cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return instanceKeys(ctx, typ, options.Tag, cache)
+}
+
+// Range calls fn for each instance of T in a stable key order, if fn returns false then stop the iteration,
+// instances deleted during the iteration will be skipped, NOTE: instances are read from the store directly, loaders are not triggered.
+func Range[T any](ctx context.Context, fn func(key any, v T) bool, opts ...Option) error {
+	options := NewOptions(opts...)
+	typ, cache, err := getInstancesCache[T](options.Tag, false, options.TypeOptions...)
+	if err != nil {
+		return err
+	}
+	keys, err := instanceKeys(ctx, typ, options.Tag, cache)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		v, err := storeGet(ctx, cache, key)
+		if err != nil {
+			if IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		tv, ok := v.(T)
+		if !ok {
+			return &TypeMismatchError{TypeId: typ.String(), Key: key, Value: v}
+		}
+		if !fn(key, tv) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// ListAny returns a page of T(specified by typeIdStr)'s instances in a stable key order,
+// which starts after the cursor(the opaque `Next` of previous page, empty for the first page) with at most limit instances,
+// if limit <= 0 then returns all the rest instances.
+func ListAny(ctx context.Context, typeIdStr string, cursor string, limit int, opts ...Option) (*Page, error) {
+	options := NewOptions(opts...)
+	typ, cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := instanceKeys(ctx, typ, options.Tag, cache)
+	if err != nil {
+		return nil, err
+	}
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		i := sort.Search(len(keys), func(i int) bool {
+			return after.less(sortKeyOf(keys[i]))
+		})
+		keys = keys[i:]
+	}
+	page := &Page{}
+	for i, key := range keys {
+		if limit > 0 && len(page.Items) == limit {
+			page.Next = encodeCursor(keys[i-1])
+			break
+		}
+		v, err := storeGet(ctx, cache, key)
+		if err != nil {
+			if IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		page.Items = append(page.Items, Entry[any]{Key: key, Value: v})
+	}
+	return page, nil
+}
+
+// instanceKeys returns sorted keys of cache's store, the store should implement `KeysInterface` or `GetAllInterface`,
+// otherwise the key index of typ(specified by `WithKeyIndex`) will be used
+func instanceKeys(ctx context.Context, typ *Type, tag string, cache interface {
+	GetCodec() codec.CodecInterface
+}) ([]any, error) {
+	s := cache.GetCodec().GetStore()
+	keys, ok, err := storeKeys(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		index := typ.keyIndex(tag)
+		if index == nil {
+			return nil, fmt.Errorf("store %s not implement KeysInterface and key index not enabled", s.GetType())
+		}
+		keys, err = index.reconcile(ctx, cache)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sortKeys(keys)
+	return keys, nil
+}
+
+// storeKeys returns unordered keys of s if s implements `KeysInterface` or `GetAllInterface`, otherwise returns false
+func storeKeys(ctx context.Context, s store.StoreInterface) ([]any, bool, error) {
+	switch s := s.(type) {
+	case KeysInterface:
+		keys, err := s.Keys(ctx)
+		return keys, true, err
+	case GetAllInterface:
+		m, err := s.GetAll(ctx)
+		if err != nil {
+			return nil, true, err
+		}
+		keys := make([]any, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		return keys, true, nil
+	}
+	return nil, false, nil
+}
+
+// instanceAll returns all instances of cache's store, the store should implement `GetAllInterface`,
+// otherwise use keys returned by `instanceKeys` to get instances one by one
+func instanceAll(ctx context.Context, typ *Type, tag string, cache interface {
+	GetCodec() codec.CodecInterface
+}) (map[any]any, error) {
+	store := cache.GetCodec().GetStore()
+	if ga, ok := store.(GetAllInterface); ok {
+		return ga.GetAll(ctx)
+	}
+	keys, err := instanceKeys(ctx, typ, tag, cache)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[any]any, len(keys))
+	for _, key := range keys {
+		v, err := store.Get(ctx, cacheKey(key))
+		if err != nil {
+			if IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// storeGet get the instance of key from cache's store directly, which bypasses loaders of the cache
+func storeGet(ctx context.Context, cache interface {
+	GetCodec() codec.CodecInterface
+}, key any) (any, error) {
+	return cache.GetCodec().GetStore().Get(ctx, cacheKey(key))
+}
+
+// cacheKey returns the key used by `cache.Cache` to access its store, which hashes keys other than string,
+// NOTE: the same as the unexported `cache.Cache.getCacheKey`
+func cacheKey(key any) any {
+	switch v := key.(type) {
+	case string:
+		return v
+	case cache.CacheKeyGenerator:
+		return v.GetCacheKey()
+	default:
+		digester := md5.New()
+		fmt.Fprint(digester, reflect.TypeOf(key))
+		fmt.Fprint(digester, key)
+		return fmt.Sprintf("%x", digester.Sum(nil))
+	}
+}
+
+// sortKey the order of a key used by `sortKeys` and cursors of `ListAny`,
+// keys are ordered by string form and then by type, so keys with the same string form(e.g. 1 and "1") are distinct
+type sortKey struct {
+	Value string `json:"v"`
+	Type  string `json:"t"`
+}
+
+func sortKeyOf(key any) sortKey {
+	if s, ok := key.(string); ok {
+		return sortKey{Value: s, Type: "string"}
+	}
+	return sortKey{Value: fmt.Sprint(key), Type: fmt.Sprintf("%T", key)}
+}
+
+func (k sortKey) less(other sortKey) bool {
+	if k.Value != other.Value {
+		return k.Value < other.Value
+	}
+	return k.Type < other.Type
+}
+
+// encodeCursor returns the opaque cursor of key
+func encodeCursor(key any) string {
+	data, _ := json.Marshal(sortKeyOf(key))
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (sortKey, error) {
+	var k sortKey
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, &k)
+	}
+	if err != nil {
+		return k, fmt.Errorf("typemap: invalid cursor %q: %v", cursor, err)
+	}
+	return k, nil
+}
@@ -0,0 +1,148 @@
+package typemap_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+type ListTest struct {
+	Value int `json:"value"`
+}
+
+func TestKeysAndRange(t *testing.T) {
+	ctx := context.Background()
+	for _, key := range []string{"c", "a", "b", "d"} {
+		err := typemap.Register(ctx, key, &ListTest{Value: int(key[0] - 'a')})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	keys, err := typemap.Keys[*ListTest](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 4 || keys[0] != "a" || keys[3] != "d" {
+		t.Fatalf("keys got %v", keys)
+	}
+	anyKeys, err := typemap.KeysAny(ctx, typemap.TypeIdOf[*ListTest]().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anyKeys) != 4 || anyKeys[1] != "b" {
+		t.Fatalf("any keys got %v", anyKeys)
+	}
+	var visited []any
+	err = typemap.Range(ctx, func(key any, v *ListTest) bool {
+		visited = append(visited, key)
+		return v.Value < 2
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(visited) != 3 || visited[2] != "c" {
+		t.Fatalf("range should stop at c, got %v", visited)
+	}
+	_, err = typemap.Keys[NotRegister](ctx)
+	if !typemap.IsNotFound(err) {
+		t.Fatalf("should not found, got %v", err)
+	}
+}
+
+func TestListAny(t *testing.T) {
+	ctx := context.Background()
+	syncStore := typemap.NewSyncMap()
+	c := typemap.NewCacheAny[ListTest](syncStore)
+	err := typemap.RegisterType[ListTest](typemap.WithInstancesCache[ListTest]("", c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range []string{"e", "b", "d", "a", "c"} {
+		err := typemap.Set(ctx, key, ListTest{Value: i})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	typeId := typemap.TypeIdOf[ListTest]().String()
+	var pages [][]any
+	var cursor string
+	for {
+		page, err := typemap.ListAny(ctx, typeId, cursor, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var keys []any
+		for _, item := range page.Items {
+			keys = append(keys, item.Key)
+			if _, ok := item.Value.(ListTest); !ok {
+				t.Fatalf("value should be ListTest, got %T", item.Value)
+			}
+		}
+		pages = append(pages, keys)
+		if page.Next == "" {
+			break
+		}
+		cursor = page.Next
+	}
+	if len(pages) != 3 {
+		t.Fatalf("should got 3 pages, got %v", pages)
+	}
+	if pages[0][0] != "a" || pages[0][1] != "b" || pages[1][0] != "c" || pages[1][1] != "d" || pages[2][0] != "e" {
+		t.Fatalf("pages got %v", pages)
+	}
+	page, err := typemap.ListAny(ctx, typeId, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 5 || page.Next != "" {
+		t.Fatalf("list all got %v", page)
+	}
+}
+
+type ListLoadTest struct {
+	Value int
+}
+
+var listLoads int32
+
+func (*ListLoadTest) Load(ctx context.Context, key any) (*ListLoadTest, error) {
+	atomic.AddInt32(&listLoads, 1)
+	return &ListLoadTest{Value: -1}, nil
+}
+
+func TestListNoLoad(t *testing.T) {
+	ctx := context.Background()
+	err := typemap.RegisterType[*ListLoadTest]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range []string{"a", "b", "c"} {
+		err := typemap.Set(ctx, key, &ListLoadTest{Value: i})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	var visited []any
+	err = typemap.Range(ctx, func(key any, v *ListLoadTest) bool {
+		visited = append(visited, key)
+		if key == "a" {
+			if err := typemap.Delete[*ListLoadTest](ctx, "b"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loads := atomic.LoadInt32(&listLoads); loads != 0 || len(visited) != 2 {
+		t.Fatalf("range should skip deleted instance without loading, got %v and %d loads", visited, loads)
+	}
+	typeId := typemap.TypeIdOf[*ListLoadTest]().String()
+	_, err = typemap.ListAny(ctx, typeId, "bad cursor", 1)
+	if err == nil {
+		t.Fatal("should failed for invalid cursor")
+	}
+}
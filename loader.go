@@ -0,0 +1,191 @@
+package typemap
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/eko/gocache/lib/v4/cache"
+	"github.com/eko/gocache/lib/v4/store"
+)
+
+// ChainLoaders returns a `cache.LoadFunction` which calls loaders in order until one returns a value or an error
+// other than not found(see `IsNotFound`), if all loaders return not found then returns the last not found error
+func ChainLoaders[T any](loaders ...cache.LoadFunction[T]) cache.LoadFunction[T] {
+	if len(loaders) == 1 {
+		return loaders[0]
+	}
+	return func(ctx context.Context, key any) (T, error) {
+		err := error(NewNotFoundError(fmt.Sprintf("no loader of %v", key)))
+		for _, load := range loaders {
+			var value T
+			value, err = load(ctx, key)
+			if err == nil || !IsNotFound(err) {
+				return value, err
+			}
+		}
+		return Zero[T](), err
+	}
+}
+
+// notFoundOnError converts all errors of load to not found, so that the next loader of chain can be tried
+func notFoundOnError[T any](load cache.LoadFunction[T]) cache.LoadFunction[T] {
+	return func(ctx context.Context, key any) (T, error) {
+		value, err := load(ctx, key)
+		if err != nil && !IsNotFound(err) {
+			err = store.NotFoundWithCause(err)
+		}
+		return value, err
+	}
+}
+
+// fallthroughOnError returns notFoundOnError(load) if enable, otherwise load itself
+func fallthroughOnError[T any](load cache.LoadFunction[T], enable bool) cache.LoadFunction[T] {
+	if !enable {
+		return load
+	}
+	return notFoundOnError(load)
+}
+
+// checkLoaders returns `*TypeMismatchError` if loaders specified by `WithLoaders` are not loaders of typeId
+func checkLoaders(typeId reflect.Type, loaders any) error {
+	if loaders == nil {
+		return nil
+	}
+	t := reflect.TypeOf(loaders)
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Func && t.Elem().NumOut() == 2 && t.Elem().Out(0) == typeId {
+		return nil
+	}
+	return &TypeMismatchError{TypeId: TypeId{typeId}.String(), Value: loaders, Err: fmt.Errorf("loaders should be []cache.LoadFunction[%s]", typeId)}
+}
+
+// resilientLoader wraps load with timeout, retry and negative cache specified by options,
+// returns load itself if none of them specified
+func resilientLoader[T any](load cache.LoadFunction[T], options *TypeOptions) cache.LoadFunction[T] {
+	if options.LoadTimeout <= 0 && options.LoadRetries <= 0 && options.NegativeCacheTTL <= 0 {
+		return load
+	}
+	negative := &negativeCache{ttl: options.NegativeCacheTTL}
+	return func(ctx context.Context, key any) (T, error) {
+		if err := negative.get(key); err != nil {
+			return Zero[T](), err
+		}
+		var value T
+		var err error
+		backoff := options.LoadRetryBackoff
+		for attempt := 0; ; attempt++ {
+			value, err = loadWithTimeout(ctx, key, load, options.LoadTimeout)
+			if err == nil {
+				return value, nil
+			}
+			if IsNotFound(err) {
+				negative.set(key, err)
+				return value, err
+			}
+			if attempt >= options.LoadRetries || ctx.Err() != nil {
+				return value, err
+			}
+			select {
+			case <-ctx.Done():
+				return value, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+}
+
+// loadWithTimeout call load with a timeout context, returns when timeout even if load ignores the context
+func loadWithTimeout[T any](ctx context.Context, key any, load cache.LoadFunction[T], timeout time.Duration) (T, error) {
+	if timeout <= 0 {
+		return load(ctx, key)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	type result struct {
+		value T
+		err   error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		value, err := load(ctx, key)
+		ch <- result{value: value, err: err}
+	}()
+	select {
+	case r := <-ch:
+		return r.value, r.err
+	case <-ctx.Done():
+		return Zero[T](), ctx.Err()
+	}
+}
+
+// negativeCache remembers not found errors of keys for ttl, non-comparable keys are not cached,
+// expired entries are swept at most once per ttl or when the cache is full, and new keys are not cached if still full
+type negativeCache struct {
+	ttl       time.Duration
+	entries   map[any]negativeEntry
+	lastSweep time.Time
+	lock      sync.Mutex
+}
+
+// maxNegativeCacheEntries the max number of keys remembered by a negativeCache
+const maxNegativeCacheEntries = 10000
+
+type negativeEntry struct {
+	err     error
+	expires time.Time
+}
+
+func (nc *negativeCache) get(key any) error {
+	if nc.ttl <= 0 || !isComparableKey(key) {
+		return nil
+	}
+	nc.lock.Lock()
+	defer nc.lock.Unlock()
+	entry, ok := nc.entries[key]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(nc.entries, key)
+		return nil
+	}
+	return entry.err
+}
+
+func (nc *negativeCache) set(key any, err error) {
+	if nc.ttl <= 0 || !isComparableKey(key) {
+		return
+	}
+	nc.lock.Lock()
+	defer nc.lock.Unlock()
+	now := time.Now()
+	if nc.entries == nil {
+		nc.entries = make(map[any]negativeEntry)
+		nc.lastSweep = now
+	}
+	if _, ok := nc.entries[key]; !ok {
+		if len(nc.entries) >= maxNegativeCacheEntries || now.Sub(nc.lastSweep) >= nc.ttl {
+			nc.sweep(now)
+		}
+		if len(nc.entries) >= maxNegativeCacheEntries {
+			return
+		}
+	}
+	nc.entries[key] = negativeEntry{
+		err:     err,
+		expires: now.Add(nc.ttl),
+	}
+}
+
+// sweep deletes expired entries, should be called with lock held
+func (nc *negativeCache) sweep(now time.Time) {
+	for key, entry := range nc.entries {
+		if now.After(entry.expires) {
+			delete(nc.entries, key)
+		}
+	}
+	nc.lastSweep = now
+}
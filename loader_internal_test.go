@@ -0,0 +1,30 @@
+package typemap
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNegativeCacheBounded(t *testing.T) {
+	errNotFound := errors.New("not found")
+	nc := &negativeCache{ttl: 10 * time.Millisecond}
+	for i := 0; i < 100; i++ {
+		nc.set(i, errNotFound)
+	}
+	time.Sleep(20 * time.Millisecond)
+	nc.set("new", errNotFound)
+	if len(nc.entries) != 1 {
+		t.Fatalf("expired entries should be swept, got %d entries", len(nc.entries))
+	}
+	nc = &negativeCache{ttl: time.Hour}
+	for i := 0; i < maxNegativeCacheEntries+100; i++ {
+		nc.set(i, errNotFound)
+	}
+	if len(nc.entries) != maxNegativeCacheEntries {
+		t.Fatalf("entries should be capped at %d, got %d", maxNegativeCacheEntries, len(nc.entries))
+	}
+	if nc.get(0) == nil || nc.get(maxNegativeCacheEntries) != nil {
+		t.Fatal("keys beyond the cap should not be cached")
+	}
+}
@@ -0,0 +1,167 @@
+package typemap_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ccmonky/typemap"
+	"go.uber.org/dig"
+)
+
+var resilientLoads = map[string]int{}
+var resilientLock sync.Mutex
+
+type ResilientType struct {
+	Value string
+}
+
+func (ResilientType) Load(ctx context.Context, key any) (*ResilientType, error) {
+	resilientLock.Lock()
+	resilientLoads[key.(string)]++
+	n := resilientLoads[key.(string)]
+	resilientLock.Unlock()
+	switch key {
+	case "flaky":
+		if n < 3 {
+			return nil, fmt.Errorf("backend unavailable")
+		}
+	case "missing":
+		return nil, typemap.NewNotFoundError("missing not found")
+	case "slow":
+		time.Sleep(200 * time.Millisecond)
+	}
+	return &ResilientType{Value: key.(string)}, nil
+}
+
+func resilientLoadsOf(key string) int {
+	resilientLock.Lock()
+	defer resilientLock.Unlock()
+	return resilientLoads[key]
+}
+
+func TestResilientLoader(t *testing.T) {
+	ctx := context.Background()
+	err := typemap.RegisterType[*ResilientType](
+		typemap.WithLoadRetry(2, time.Millisecond),
+		typemap.WithLoadTimeout(20*time.Millisecond),
+		typemap.WithNegativeCache(time.Hour),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := typemap.Get[*ResilientType](ctx, "flaky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Value != "flaky" {
+		t.Fatal("should ==")
+	}
+	if n := resilientLoadsOf("flaky"); n != 3 {
+		t.Fatalf("should load 3 times, got %d", n)
+	}
+	for i := 0; i < 3; i++ {
+		_, err = typemap.Get[*ResilientType](ctx, "missing")
+		if !typemap.IsNotFound(err) {
+			t.Fatalf("should be not found, got %v", err)
+		}
+	}
+	if n := resilientLoadsOf("missing"); n != 1 {
+		t.Fatalf("not found should be cached and not retried, got %d loads", n)
+	}
+	_, err = typemap.Get[*ResilientType](ctx, "slow")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("should timeout, got %v", err)
+	}
+	if n := resilientLoadsOf("slow"); n != 3 {
+		t.Fatalf("timeout should be retried, got %d loads", n)
+	}
+}
+
+type ChainType struct {
+	Source string
+}
+
+func (ChainType) Load(ctx context.Context, key any) (*ChainType, error) {
+	if key == "file" {
+		return &ChainType{Source: "file"}, nil
+	}
+	return nil, typemap.NewNotFoundError(fmt.Sprintf("%v not found in file", key))
+}
+
+func (ChainType) Default() *ChainType {
+	return &ChainType{Source: "default"}
+}
+
+func TestChainLoaders(t *testing.T) {
+	ctx := context.Background()
+	custom := func(ctx context.Context, key any) (*ChainType, error) {
+		switch key {
+		case "custom":
+			return &ChainType{Source: "custom"}, nil
+		case "broken":
+			return nil, errors.New("custom loader broken")
+		}
+		return nil, typemap.NewNotFoundError(fmt.Sprintf("%v not found in custom", key))
+	}
+	err := typemap.RegisterType[*ChainType](typemap.WithLoaders(custom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, source := range map[string]string{
+		"custom": "custom",
+		"file":   "file",
+		"other":  "default",
+	} {
+		v, err := typemap.Get[*ChainType](ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Source != source {
+			t.Fatalf("%s should load from %s, got %s", key, source, v.Source)
+		}
+	}
+	_, err = typemap.Get[*ChainType](ctx, "broken")
+	if err == nil || typemap.IsNotFound(err) {
+		t.Fatalf("error other than not found should stop the chain, got %v", err)
+	}
+}
+
+type DIFallbackType struct {
+	Source string
+}
+
+func (DIFallbackType) Load(ctx context.Context, key any) (*DIFallbackType, error) {
+	return nil, errors.New("file broken")
+}
+
+func TestChainLoadersDIFallback(t *testing.T) {
+	c := dig.New()
+	err := c.Provide(func() *DIFallbackType {
+		return &DIFallbackType{Source: "di"}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typemap.SetContainer(c)
+	err = typemap.RegisterType[*DIFallbackType](typemap.WithEnableDI(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := typemap.Get[*DIFallbackType](context.Background(), "any")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Source != "di" {
+		t.Fatalf("any error of Load should fall through to DI, got %s", v.Source)
+	}
+	err = typemap.RegisterType[*DIFallbackType](typemap.WithTypeMapName("loaders"), typemap.WithLoaders(func(ctx context.Context, key any) (*ChainType, error) {
+		return nil, nil
+	}))
+	if !typemap.IsTypeMismatch(err) {
+		t.Fatalf("loaders of other type should be rejected, got %v", err)
+	}
+}
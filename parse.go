@@ -0,0 +1,573 @@
+package typemap
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TypeDescriptor the structural description of a type id string parsed by `ParseTypeId`
+// - named types(include builtin types) have Name, and PkgPath if known, generic instantiations also have TypeArgs
+// - composite types have Kind(Ptr, Slice, Array, Map, Chan, Func) and their element descriptors
+// - struct and interface literals have Kind(Struct, Interface) and Literal, e.g. `struct { A int }`
+type TypeDescriptor struct {
+	Kind     reflect.Kind      `json:"kind,omitempty"`
+	PkgPath  string            `json:"pkg_path,omitempty"`
+	Name     string            `json:"name,omitempty"`
+	TypeArgs []*TypeDescriptor `json:"type_args,omitempty"`
+	Literal  string            `json:"literal,omitempty"`
+	Len      int               `json:"len,omitempty"`
+	ChanDir  reflect.ChanDir   `json:"chan_dir,omitempty"`
+	Key      *TypeDescriptor   `json:"key,omitempty"`
+	Elem     *TypeDescriptor   `json:"elem,omitempty"`
+	In       []*TypeDescriptor `json:"in,omitempty"`
+	Out      []*TypeDescriptor `json:"out,omitempty"`
+	Variadic bool              `json:"variadic,omitempty"`
+}
+
+// ParseTypeId parse a type id string into a `*TypeDescriptor`, which accepts:
+// - the form of `TypeId.String()`, e.g. `github.com/ccmonky/typemap:*typemap.Type`, `[]*typemap.Type`
+// - named types with package path inline, e.g. `[]*github.com/ccmonky/typemap:typemap.Type` or `[]*github.com/ccmonky/typemap:Type`
+// - named types qualified by package path as in generic type arguments, e.g. `map[string]*net/http.Request`
+func ParseTypeId(typeIdStr string) (*TypeDescriptor, error) {
+	p := &typeIdParser{s: typeIdStr}
+	var pkgPath string
+	if i := strings.Index(typeIdStr, ":"); i > 0 && !strings.ContainsAny(typeIdStr[:i], "[]*() ") &&
+		strings.HasPrefix(typeIdStr[i+1:], "*") {
+		pkgPath = typeIdStr[:i] // NOTE: `TypeId.String()` form of pointer, the package path belongs to the innermost named type
+		p.pos = i + 1
+	}
+	d, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos != len(p.s) {
+		return nil, p.errorf("unexpected trailing %q", p.s[p.pos:])
+	}
+	if pkgPath != "" {
+		named := d
+		for named.Kind == reflect.Ptr {
+			named = named.Elem
+		}
+		named.PkgPath = pkgPath
+	}
+	return d, nil
+}
+
+// String returns the string representation of the type, equal to `reflect.Type.String()`
+func (d *TypeDescriptor) String() string {
+	var b strings.Builder
+	d.format(&b, false)
+	return b.String()
+}
+
+// TypeId returns the type id string representation, equal to `TypeId.String()`
+func (d *TypeDescriptor) TypeId() string {
+	named := d
+	for named.Kind == reflect.Ptr {
+		named = named.Elem
+	}
+	if named.PkgPath == "" {
+		return d.String()
+	}
+	return named.PkgPath + ":" + d.String()
+}
+
+// format write the string representation into b, named types in generic type arguments are qualified by package path
+func (d *TypeDescriptor) format(b *strings.Builder, qualified bool) {
+	switch {
+	case d.Name != "":
+		name := d.Name
+		if qualified && d.PkgPath != "" {
+			name = d.PkgPath + name[strings.Index(name, "."):]
+		}
+		b.WriteString(name)
+		if len(d.TypeArgs) > 0 {
+			b.WriteByte('[')
+			for i, arg := range d.TypeArgs {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				arg.format(b, true)
+			}
+			b.WriteByte(']')
+		}
+	case d.Literal != "":
+		b.WriteString(d.Literal)
+	case d.Kind == reflect.Ptr:
+		b.WriteByte('*')
+		d.Elem.format(b, qualified)
+	case d.Kind == reflect.Slice:
+		b.WriteString("[]")
+		d.Elem.format(b, qualified)
+	case d.Kind == reflect.Array:
+		b.WriteString("[" + strconv.Itoa(d.Len) + "]")
+		d.Elem.format(b, qualified)
+	case d.Kind == reflect.Map:
+		b.WriteString("map[")
+		d.Key.format(b, qualified)
+		b.WriteByte(']')
+		d.Elem.format(b, qualified)
+	case d.Kind == reflect.Chan:
+		switch d.ChanDir {
+		case reflect.RecvDir:
+			b.WriteString("<-chan ")
+		case reflect.SendDir:
+			b.WriteString("chan<- ")
+		default:
+			b.WriteString("chan ")
+		}
+		d.Elem.format(b, qualified)
+	case d.Kind == reflect.Func:
+		b.WriteString("func(")
+		for i, in := range d.In {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if d.Variadic && i == len(d.In)-1 {
+				b.WriteString("...")
+				in.Elem.format(b, qualified)
+				continue
+			}
+			in.format(b, qualified)
+		}
+		b.WriteByte(')')
+		switch len(d.Out) {
+		case 0:
+		case 1:
+			b.WriteByte(' ')
+			d.Out[0].format(b, qualified)
+		default:
+			b.WriteString(" (")
+			for i, out := range d.Out {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				out.format(b, qualified)
+			}
+			b.WriteByte(')')
+		}
+	}
+}
+
+// Resolve resolve the descriptor to reflect.Type, named types are resolved against builtin types
+// and types reachable from the registered types of the TypeMap(specified by `WithTypeMapName`)
+func (d *TypeDescriptor) Resolve(opts ...TypeOption) (reflect.Type, error) {
+	return d.resolve(reachableTypes(opts...))
+}
+
+func (d *TypeDescriptor) resolve(index map[string][]reflect.Type) (reflect.Type, error) {
+	switch {
+	case d.Name != "" || d.Literal != "":
+		s := d.String()
+		if t, ok := builtinTypes[s]; ok && d.PkgPath == "" {
+			return t, nil
+		}
+		var candidates []reflect.Type
+		for _, t := range index[s] {
+			if d.PkgPath == "" || t.PkgPath() == d.PkgPath {
+				candidates = append(candidates, t)
+			}
+		}
+		if len(candidates) == 0 && d.PkgPath != "" && len(d.TypeArgs) == 0 { // NOTE: package name may differ from the last element of path
+			candidates = index[d.PkgPath+"."+d.Name[strings.LastIndex(d.Name, ".")+1:]]
+		}
+		switch len(candidates) {
+		case 0:
+			return nil, NewNotFoundError(fmt.Sprintf("type %s not found", s))
+		case 1:
+			return candidates[0], nil
+		}
+		return nil, fmt.Errorf("typemap: type %s is ambiguous, specify the package path", s)
+	case d.Kind == reflect.Ptr:
+		elem, err := d.Elem.resolve(index)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PointerTo(elem), nil
+	case d.Kind == reflect.Slice:
+		elem, err := d.Elem.resolve(index)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+	case d.Kind == reflect.Array:
+		elem, err := d.Elem.resolve(index)
+		if err != nil {
+			return nil, err
+		}
+		if d.Len < 0 || d.Len > maxArrayLen || (elem.Size() > 0 && uintptr(d.Len) > maxArraySize/elem.Size()) {
+			return nil, fmt.Errorf("typemap: array length %d of %s out of range", d.Len, elem)
+		}
+		return reflect.ArrayOf(d.Len, elem), nil
+	case d.Kind == reflect.Map:
+		key, err := d.Key.resolve(index)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := d.Elem.resolve(index)
+		if err != nil {
+			return nil, err
+		}
+		if !key.Comparable() {
+			return nil, fmt.Errorf("typemap: invalid map key type %s", key)
+		}
+		return reflect.MapOf(key, elem), nil
+	case d.Kind == reflect.Chan:
+		elem, err := d.Elem.resolve(index)
+		if err != nil {
+			return nil, err
+		}
+		if elem.Size() >= maxChanElemSize {
+			return nil, fmt.Errorf("typemap: channel element type %s too large", elem)
+		}
+		if d.ChanDir != reflect.RecvDir && d.ChanDir != reflect.SendDir && d.ChanDir != reflect.BothDir {
+			return nil, fmt.Errorf("typemap: invalid channel direction %d", d.ChanDir)
+		}
+		return reflect.ChanOf(d.ChanDir, elem), nil
+	case d.Kind == reflect.Func:
+		in := make([]reflect.Type, len(d.In))
+		for i, id := range d.In {
+			t, err := id.resolve(index)
+			if err != nil {
+				return nil, err
+			}
+			in[i] = t
+		}
+		out := make([]reflect.Type, len(d.Out))
+		for i, od := range d.Out {
+			t, err := od.resolve(index)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = t
+		}
+		if len(in)+len(out) > maxFuncArgs {
+			return nil, fmt.Errorf("typemap: func type has too many arguments %d", len(in)+len(out))
+		}
+		if d.Variadic && (len(in) == 0 || in[len(in)-1].Kind() != reflect.Slice) {
+			return nil, fmt.Errorf("typemap: last argument of variadic func type must be slice")
+		}
+		return reflect.FuncOf(in, out, d.Variadic), nil
+	}
+	return nil, fmt.Errorf("typemap: invalid type descriptor %s", d.String())
+}
+
+// limits of composite types parsed and resolved, reflect.ArrayOf, ChanOf and FuncOf panic beyond them
+const (
+	maxArrayLen     = 1 << 20
+	maxArraySize    = 1 << 30
+	maxChanElemSize = 1 << 16
+	maxFuncArgs     = 128
+)
+
+var builtinTypes = map[string]reflect.Type{
+	"bool":         TypeOf[bool](),
+	"int":          TypeOf[int](),
+	"int8":         TypeOf[int8](),
+	"int16":        TypeOf[int16](),
+	"int32":        TypeOf[int32](),
+	"int64":        TypeOf[int64](),
+	"uint":         TypeOf[uint](),
+	"uint8":        TypeOf[uint8](),
+	"uint16":       TypeOf[uint16](),
+	"uint32":       TypeOf[uint32](),
+	"uint64":       TypeOf[uint64](),
+	"uintptr":      TypeOf[uintptr](),
+	"float32":      TypeOf[float32](),
+	"float64":      TypeOf[float64](),
+	"complex64":    TypeOf[complex64](),
+	"complex128":   TypeOf[complex128](),
+	"string":       TypeOf[string](),
+	"byte":         TypeOf[byte](),
+	"rune":         TypeOf[rune](),
+	"error":        TypeOf[error](),
+	"any":          TypeOf[any](),
+	"interface {}": TypeOf[any](),
+	"struct {}":    TypeOf[struct{}](),
+}
+
+// reachableTypes returns types reachable from the registered types of the TypeMap specified by opts,
+// indexed by string and `pkgPath.Name` of named types, which is cached until the TypeMap changes
+func reachableTypes(opts ...TypeOption) map[string][]reflect.Type {
+	table := loadTypeMap(opts...).load()
+	table.reachableOnce.Do(func() {
+		table.reachable = indexReachableTypes(table.types)
+	})
+	return table.reachable
+}
+
+func indexReachableTypes(types map[reflect.Type]*Type) map[string][]reflect.Type {
+	index := make(map[string][]reflect.Type)
+	visited := make(map[reflect.Type]bool)
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		index[t.String()] = append(index[t.String()], t)
+		if t.PkgPath() != "" && t.Name() != "" {
+			if key := t.PkgPath() + "." + t.Name(); key != t.String() {
+				index[key] = append(index[key], t)
+			}
+		}
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+			walk(t.Elem())
+		case reflect.Map:
+			walk(t.Key())
+			walk(t.Elem())
+		case reflect.Func:
+			for i := 0; i < t.NumIn(); i++ {
+				walk(t.In(i))
+			}
+			for i := 0; i < t.NumOut(); i++ {
+				walk(t.Out(i))
+			}
+		case reflect.Struct:
+			for i := 0; i < t.NumField(); i++ {
+				walk(t.Field(i).Type)
+			}
+		}
+	}
+	for t := range types {
+		walk(t)
+	}
+	return index
+}
+
+type typeIdParser struct {
+	s   string
+	pos int
+}
+
+func (p *typeIdParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("typemap: parse type id %q at %d: %s", p.s, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *typeIdParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *typeIdParser) consume(prefix string) bool {
+	if strings.HasPrefix(p.s[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (p *typeIdParser) parse() (*TypeDescriptor, error) {
+	p.skipSpaces()
+	switch {
+	case p.pos >= len(p.s):
+		return nil, p.errorf("unexpected end")
+	case p.consume("*"):
+		return p.parseElem(&TypeDescriptor{Kind: reflect.Ptr})
+	case p.consume("[]"):
+		return p.parseElem(&TypeDescriptor{Kind: reflect.Slice})
+	case p.consume("["):
+		end := strings.IndexByte(p.s[p.pos:], ']')
+		if end < 0 {
+			return nil, p.errorf("array length not closed")
+		}
+		n, err := strconv.Atoi(p.s[p.pos : p.pos+end])
+		if err != nil {
+			return nil, p.errorf("invalid array length: %v", err)
+		}
+		if n < 0 || n > maxArrayLen {
+			return nil, p.errorf("array length %d out of range [0, %d]", n, maxArrayLen)
+		}
+		p.pos += end + 1
+		return p.parseElem(&TypeDescriptor{Kind: reflect.Array, Len: n})
+	case p.consume("map["):
+		key, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume("]") {
+			return nil, p.errorf("map key not closed")
+		}
+		return p.parseElem(&TypeDescriptor{Kind: reflect.Map, Key: key})
+	case p.consume("<-chan "):
+		return p.parseElem(&TypeDescriptor{Kind: reflect.Chan, ChanDir: reflect.RecvDir})
+	case p.consume("chan<- "):
+		return p.parseElem(&TypeDescriptor{Kind: reflect.Chan, ChanDir: reflect.SendDir})
+	case p.consume("chan "):
+		return p.parseElem(&TypeDescriptor{Kind: reflect.Chan, ChanDir: reflect.BothDir})
+	case p.consume("func("):
+		return p.parseFunc()
+	case strings.HasPrefix(p.s[p.pos:], "struct {"):
+		return p.parseLiteral(reflect.Struct)
+	case strings.HasPrefix(p.s[p.pos:], "interface {"):
+		return p.parseLiteral(reflect.Interface)
+	}
+	return p.parseNamed()
+}
+
+func (p *typeIdParser) parseElem(d *TypeDescriptor) (*TypeDescriptor, error) {
+	elem, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	d.Elem = elem
+	return d, nil
+}
+
+func (p *typeIdParser) parseFunc() (*TypeDescriptor, error) {
+	d := &TypeDescriptor{Kind: reflect.Func}
+	p.skipSpaces()
+	for !p.consume(")") {
+		if len(d.In) > 0 && !p.consume(",") {
+			return nil, p.errorf("expect , or ) in func params")
+		}
+		p.skipSpaces()
+		if p.consume("...") {
+			elem, err := p.parse()
+			if err != nil {
+				return nil, err
+			}
+			d.In = append(d.In, &TypeDescriptor{Kind: reflect.Slice, Elem: elem})
+			d.Variadic = true
+			continue
+		}
+		in, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		d.In = append(d.In, in)
+		p.skipSpaces()
+	}
+	if !p.consume(" ") || p.pos >= len(p.s) || strings.ContainsRune(",])", rune(p.s[p.pos])) {
+		return d, nil // NOTE: no results
+	}
+	if !p.consume("(") {
+		out, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		d.Out = append(d.Out, out)
+		return d, nil
+	}
+	for !p.consume(")") {
+		if len(d.Out) > 0 && !p.consume(",") {
+			return nil, p.errorf("expect , or ) in func results")
+		}
+		out, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		d.Out = append(d.Out, out)
+		p.skipSpaces()
+	}
+	return d, nil
+}
+
+func (p *typeIdParser) parseLiteral(kind reflect.Kind) (*TypeDescriptor, error) {
+	start := p.pos
+	depth := 0
+	inQuote := false
+	for ; p.pos < len(p.s); p.pos++ {
+		switch c := p.s[p.pos]; {
+		case inQuote && c == '\\':
+			p.pos++
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				p.pos++
+				return &TypeDescriptor{Kind: kind, Literal: p.s[start:p.pos]}, nil
+			}
+		}
+	}
+	return nil, p.errorf("%s literal not closed", kind)
+}
+
+func (p *typeIdParser) parseNamed() (*TypeDescriptor, error) {
+	start := p.pos
+	for p.pos < len(p.s) {
+		r := rune(p.s[p.pos])
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r >= unicode.MaxASCII || strings.ContainsRune("_./-~:", r)) {
+			break
+		}
+		p.pos++
+	}
+	token := p.s[start:p.pos]
+	if token == "" {
+		return nil, p.errorf("unexpected %q", p.s[p.pos:])
+	}
+	d := &TypeDescriptor{}
+	if i := strings.LastIndex(token, ":"); i >= 0 { // NOTE: `pkgPath:name` form
+		d.PkgPath, d.Name = token[:i], token[i+1:]
+		if !strings.Contains(d.Name, ".") {
+			d.Name = packageName(d.PkgPath) + "." + d.Name
+		}
+	} else if i := strings.LastIndex(token, "/"); i >= 0 { // NOTE: `pkgPath.name` form used by generic type arguments
+		dot := strings.LastIndex(token[i:], ".") // NOTE: the last element of path may contain dot, e.g. gopkg.in/yaml.v3.Node
+		if dot < 0 || !validPkgPath(token[:i+dot]) || token[i+dot+1:] == "" {
+			return nil, p.errorf("invalid type name %s", token)
+		}
+		d.PkgPath = token[:i+dot]
+		d.Name = packageName(d.PkgPath) + token[i+dot:]
+	} else {
+		d.Name = token
+		if t, ok := builtinTypes[token]; ok {
+			d.Kind = t.Kind()
+		}
+	}
+	if p.consume("[") {
+		for !p.consume("]") {
+			if len(d.TypeArgs) > 0 && !p.consume(",") {
+				return nil, p.errorf("expect , or ] in type arguments")
+			}
+			arg, err := p.parse()
+			if err != nil {
+				return nil, err
+			}
+			d.TypeArgs = append(d.TypeArgs, arg)
+		}
+	}
+	return d, nil
+}
+
+// packageName guess the package name of pkgPath by convention, e.g. the name of `gopkg.in/yaml.v3`
+// and `github.com/foo/bar/v2` are yaml and bar, NOTE: the real package name may differ
+func packageName(pkgPath string) string {
+	name := path.Base(pkgPath)
+	if isMajorVersion(name) && strings.Contains(pkgPath, "/") {
+		name = path.Base(path.Dir(pkgPath))
+	}
+	if i := strings.LastIndex(name, "."); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+	return name
+}
+
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(s[1:])
+	return err == nil
+}
+
+// validPkgPath reports whether pkgPath consists of non-empty elements not starting or ending with dot
+func validPkgPath(pkgPath string) bool {
+	for _, elem := range strings.Split(pkgPath, "/") {
+		if elem == "" || elem[0] == '.' || elem[len(elem)-1] == '.' {
+			return false
+		}
+	}
+	return true
+}
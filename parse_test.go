@@ -0,0 +1,130 @@
+package typemap_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+type ParseTest struct {
+	Handler http.Handler
+}
+
+type GenericParseTest[K comparable, V any] struct{}
+
+func TestParseTypeId(t *testing.T) {
+	tm := typemap.WithTypeMapName("parse")
+	types := []reflect.Type{
+		typemap.TypeOf[int](),
+		typemap.TypeOf[*ParseTest](),
+		typemap.TypeOf[[]*ParseTest](),
+		typemap.TypeOf[[3]map[string]**ParseTest](),
+		typemap.TypeOf[map[string][]*http.Request](),
+		typemap.TypeOf[func(int, ...string) (bool, error)](),
+		typemap.TypeOf[func(func(), string) *ParseTest](),
+		typemap.TypeOf[<-chan chan<- error](),
+		typemap.TypeOf[any](),
+		typemap.TypeOf[struct {
+			A int `json:"a"`
+		}](),
+		typemap.TypeOf[GenericParseTest[string, *http.Request]](),
+		typemap.TypeOf[*GenericParseTest[int, []ParseTest]](),
+	}
+	for _, typ := range types {
+		typeIdStr := typemap.TypeId{Type: typ}.String()
+		d, err := typemap.ParseTypeId(typeIdStr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.String() != typ.String() {
+			t.Fatalf("string should == %s, got %s", typ.String(), d.String())
+		}
+		if d.TypeId() != typeIdStr {
+			t.Fatalf("type id should == %s, got %s", typeIdStr, d.TypeId())
+		}
+	}
+	err := typemap.RegisterType[[]*ParseTest](tm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.RegisterType[*GenericParseTest[string, *http.Request]](tm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, typ := range types {
+		d, err := typemap.ParseTypeId(typemap.TypeId{Type: typ}.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch typ {
+		case typemap.TypeOf[map[string][]*http.Request](), // NOTE: http.Request is not reachable from registered types
+			typemap.TypeOf[*GenericParseTest[int, []ParseTest]](),
+			typemap.TypeOf[struct {
+				A int `json:"a"`
+			}]():
+			continue
+		}
+		if r, err := d.Resolve(tm); err != nil || r != typ {
+			t.Fatalf("%s should resolve, got %v, %v", typ, r, err)
+		}
+	}
+	for _, typeIdStr := range []string{
+		"[]*github.com/ccmonky/typemap_test:ParseTest",
+		"[]*github.com/ccmonky/typemap_test:typemap_test.ParseTest",
+		"[]*github.com/ccmonky/typemap_test.ParseTest",
+	} {
+		typ := typemap.ResolveTypeByID(typeIdStr, tm)
+		if typ == nil || typ.TypeId() != typemap.TypeOf[[]*ParseTest]() {
+			t.Fatalf("%s should got []*ParseTest, got %v", typeIdStr, typ)
+		}
+	}
+	for _, typeIdStr := range []string{"map[int", "func(int", "[x]int", "*", "int]", "[-1]int", "[99999999999]int"} {
+		if _, err := typemap.ParseTypeId(typeIdStr); err == nil {
+			t.Fatalf("%s should be invalid", typeIdStr)
+		}
+		if typ := typemap.ResolveTypeByID(typeIdStr, tm); typ != nil {
+			t.Fatalf("%s should not found, got %v", typeIdStr, typ)
+		}
+	}
+	for typeIdStr, want := range map[string][2]string{
+		"*gopkg.in/yaml.v3.Node":          {"gopkg.in/yaml.v3", "*yaml.Node"},
+		"[]github.com/foo/bar/v2.Baz":     {"github.com/foo/bar/v2", "[]bar.Baz"},
+		"map[string]example.com/x.y.Type": {"example.com/x.y", "map[string]x.y.Type"},
+	} {
+		d, err := typemap.ParseTypeId(typeIdStr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Elem.PkgPath != want[0] || d.String() != want[1] {
+			t.Fatalf("%s should parse to %v, got %s, %s", typeIdStr, want, d.Elem.PkgPath, d.String())
+		}
+	}
+	if typemap.GetTypeByID("[]*github.com/ccmonky/typemap_test.ParseTest", tm) != nil {
+		t.Fatal("GetTypeByID should only look up the exact type id or alias")
+	}
+	for _, typeIdStr := range []string{"[1048576][1048576]int", "chan [70000]uint8"} {
+		d, err := typemap.ParseTypeId(typeIdStr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.Resolve(tm); err == nil {
+			t.Fatalf("%s should failed for too large element", typeIdStr)
+		}
+	}
+	for _, d := range []*typemap.TypeDescriptor{
+		{Kind: reflect.Chan, Elem: &typemap.TypeDescriptor{Name: "int"}},
+		{Kind: reflect.Func, In: []*typemap.TypeDescriptor{{Name: "int"}}, Variadic: true},
+		{Kind: reflect.Func, In: make([]*typemap.TypeDescriptor, 129)},
+	} {
+		for i := range d.In {
+			if d.In[i] == nil {
+				d.In[i] = &typemap.TypeDescriptor{Name: "int"}
+			}
+		}
+		if _, err := d.Resolve(tm); err == nil {
+			t.Fatalf("%v should be invalid", d.Kind)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package typemap
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PendingRefs collects the references of `Ref` and `RefAttr` unmarshaled by `Unmarshal` instead of resolving them,
+// and resolves them together later by `ResolvePending`, so that a config can reference an instance defined later in the same file
+// (e.g. registered by a later `Reg`), e.g.
+//
+// pending := typemap.NewPendingRefs()
+// err := pending.Unmarshal(data, &config) // Reg registers instances, Ref&RefAttr only recorded
+// err = pending.ResolvePending(ctx)       // reports all dangling references
+//
+// NOTE:
+// - `Unmarshal` walks v by reflection and passes p to `Ref`, `RefAttr`, `Reg` and `Poly` explicitly, so unmarshals elsewhere are not affected
+// - values of other types implementing `json.Unmarshaler` decode themselves, so refs nested in them are resolved immediately as `json.Unmarshal`
+// - copies of a ref share its cached value, so refs copied after unmarshal(e.g. values of map) are also primed by `ResolvePending`
+type PendingRefs struct {
+	refs []pendingRef
+	lock sync.Mutex
+}
+
+// NewPendingRefs create a new `*PendingRefs`
+func NewPendingRefs() *PendingRefs {
+	return &PendingRefs{}
+}
+
+// Unmarshal unmarshal data into v like `json.Unmarshal`, with references recorded into p
+func (p *PendingRefs) Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+	if !json.Valid(data) {
+		return json.Unmarshal(data, v) // NOTE: returns the syntax error
+	}
+	return p.decode(bytes.TrimSpace(data), rv.Elem())
+}
+
+// Len returns the number of references not resolved yet
+func (p *PendingRefs) Len() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.refs)
+}
+
+// ResolvePending resolves all recorded references and clears them,
+// returns a `*MultiError` keyed by reference description listing all dangling references
+func (p *PendingRefs) ResolvePending(ctx context.Context) error {
+	p.lock.Lock()
+	refs := p.refs
+	p.refs = nil
+	p.lock.Unlock()
+	me := &MultiError{}
+	for _, ref := range refs {
+		if err := ref.resolve(ctx); err != nil {
+			me.add(ref.key, err)
+		}
+	}
+	return me.errorOrNil()
+}
+
+func (p *PendingRefs) add(key string, resolve func(ctx context.Context) error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.refs = append(p.refs, pendingRef{key: key, resolve: resolve})
+}
+
+type pendingRef struct {
+	key     string
+	resolve func(ctx context.Context) error
+}
+
+// pendingUnmarshaler implemented by types which record references into pending instead of resolving them
+type pendingUnmarshaler interface {
+	unmarshalPending(pending *PendingRefs, b []byte) error
+}
+
+// decode decodes data into the settable v, values which can not contain a `pendingUnmarshaler` are decoded by `json.Unmarshal`
+func (p *PendingRefs) decode(data []byte, v reflect.Value) error {
+	if pu, ok := v.Addr().Interface().(pendingUnmarshaler); ok {
+		return pu.unmarshalPending(p, data)
+	}
+	if !hasPendingUnmarshaler(v.Type()) {
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+	if string(data) == "null" {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Slice:
+			v.Set(reflect.Zero(v.Type()))
+		}
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return p.decode(data, v.Elem())
+	case reflect.Struct:
+		if data[0] != '{' {
+			break
+		}
+		fields := jsonFields(v.Type())
+		return decodeObject(data, func(key string, value []byte) error {
+			f := fields.lookup(key)
+			if f == nil {
+				return nil
+			}
+			fv := v
+			for _, i := range f.index {
+				if fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						if !fv.CanSet() {
+							return fmt.Errorf("typemap: cannot set embedded pointer to unexported struct %s", fv.Type().Elem())
+						}
+						fv.Set(reflect.New(fv.Type().Elem()))
+					}
+					fv = fv.Elem()
+				}
+				fv = fv.Field(i)
+			}
+			return p.decode(value, fv)
+		})
+	case reflect.Map:
+		if data[0] != '{' {
+			break
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		return decodeObject(data, func(key string, value []byte) error {
+			kv, err := mapKey(key, v.Type().Key())
+			if err != nil {
+				return err
+			}
+			ev := reflect.New(v.Type().Elem()).Elem()
+			err = p.decode(value, ev)
+			if err != nil {
+				return err
+			}
+			v.SetMapIndex(kv, ev)
+			return nil
+		})
+	case reflect.Slice, reflect.Array:
+		if data[0] != '[' {
+			break
+		}
+		var items []json.RawMessage
+		err := json.Unmarshal(data, &items)
+		if err != nil {
+			return err
+		}
+		if v.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(v.Type(), len(items), len(items)))
+		}
+		for i := 0; i < v.Len(); i++ {
+			if i >= len(items) {
+				v.Index(i).Set(reflect.Zero(v.Type().Elem()))
+				continue
+			}
+			err = p.decode(items[i], v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return json.Unmarshal(data, v.Addr().Interface()) // NOTE: returns the type error
+}
+
+// decodeObject calls fn for each member of the json object data in order
+func decodeObject(data []byte, fn func(key string, value []byte) error) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var value json.RawMessage
+		err = dec.Decode(&value)
+		if err != nil {
+			return err
+		}
+		err = fn(tok.(string), value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapKey converts the json object key to a map key of t as `json.Unmarshal`
+func mapKey(key string, t reflect.Type) (reflect.Value, error) {
+	kv := reflect.New(t)
+	if tu, ok := kv.Interface().(encoding.TextUnmarshaler); ok && t.Kind() != reflect.String {
+		return kv.Elem(), tu.UnmarshalText([]byte(key))
+	}
+	switch t.Kind() {
+	case reflect.String:
+		kv.Elem().SetString(key)
+		return kv.Elem(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(key, 10, 64)
+		if err == nil && !kv.Elem().OverflowInt(n) {
+			kv.Elem().SetInt(n)
+			return kv.Elem(), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(key, 10, 64)
+		if err == nil && !kv.Elem().OverflowUint(n) {
+			kv.Elem().SetUint(n)
+			return kv.Elem(), nil
+		}
+	}
+	return kv.Elem(), &json.UnmarshalTypeError{Value: "number " + key, Type: t}
+}
+
+type jsonField struct {
+	name  string
+	index []int
+}
+
+type jsonFieldList []jsonField
+
+// lookup returns the field of key, an exact match is preferred over a case-insensitive match as `json.Unmarshal`
+func (fields jsonFieldList) lookup(key string) *jsonField {
+	var fold *jsonField
+	for i := range fields {
+		if fields[i].name == key {
+			return &fields[i]
+		}
+		if fold == nil && strings.EqualFold(fields[i].name, key) {
+			fold = &fields[i]
+		}
+	}
+	return fold
+}
+
+// jsonFields returns the fields of struct t decoded by json, fields of embedded structs are promoted,
+// a field hides the fields of the same name in deeper embedded structs
+func jsonFields(t reflect.Type) jsonFieldList {
+	var fields jsonFieldList
+	names := make(map[string]bool)
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	current := []embedded{{typ: t}}
+	visited := map[reflect.Type]bool{t: true}
+	for len(current) > 0 {
+		var next []embedded
+		depth := make(map[string]bool)
+		for _, e := range current {
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				index := append(e.index[:len(e.index):len(e.index)], i)
+				ft := sf.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if !sf.IsExported() && !(sf.Anonymous && ft.Kind() == reflect.Struct) {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, _, _ := strings.Cut(tag, ",")
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					if !visited[ft] {
+						visited[ft] = true
+						next = append(next, embedded{typ: ft, index: index})
+					}
+					continue
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				if name == "" {
+					name = sf.Name
+				}
+				if names[name] {
+					continue
+				}
+				depth[name] = true
+				fields = append(fields, jsonField{name: name, index: index})
+			}
+		}
+		for name := range depth {
+			names[name] = true
+		}
+		current = next
+	}
+	return fields
+}
+
+var (
+	pendingUnmarshalerType = reflect.TypeOf((*pendingUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType    = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType    = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	pendingTypes           sync.Map
+)
+
+// hasPendingUnmarshaler reports whether values of t may contain a `pendingUnmarshaler` decoded by `PendingRefs`
+func hasPendingUnmarshaler(t reflect.Type) bool {
+	if has, ok := pendingTypes.Load(t); ok {
+		return has.(bool)
+	}
+	has := containsPendingUnmarshaler(t, make(map[reflect.Type]bool))
+	pendingTypes.Store(t, has)
+	return has
+}
+
+func containsPendingUnmarshaler(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	pt := reflect.PointerTo(t)
+	if pt.Implements(pendingUnmarshalerType) {
+		return true
+	}
+	if pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType) || visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return containsPendingUnmarshaler(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if sf := t.Field(i); (sf.IsExported() || sf.Anonymous) && containsPendingUnmarshaler(sf.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
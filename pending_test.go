@@ -0,0 +1,96 @@
+package typemap_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+type PendingDB struct {
+	DSN string
+}
+
+type PendingService struct {
+	DB typemap.Ref[*PendingDB] `json:"db"`
+}
+
+type PendingConfig struct {
+	Main     typemap.Ref[*PendingDB]             `json:"main"`
+	DSN      typemap.RefAttr[*PendingDB, string] `json:"dsn"`
+	Missing  typemap.Ref[*PendingDB]             `json:"missing"`
+	Replicas map[string]typemap.Ref[*PendingDB]  `json:"replicas"`
+	Services []*typemap.Reg[*PendingService]     `json:"services"`
+	DB       typemap.Reg[*PendingDB]             `json:"db"`
+}
+
+func TestPendingRefs(t *testing.T) {
+	ctx := context.Background()
+	typemap.MustRegisterType[*PendingDB]()
+	typemap.MustRegisterType[*PendingService]()
+	data := []byte(`{
+		"main": "pending-main",
+		"dsn": {"name": "pending-main", "attr": "DSN", "cache": true},
+		"missing": {"name": "pending-missing"},
+		"replicas": {"r1": "pending-main"},
+		"services": [{"name": "pending-service", "value": {"db": "pending-main"}}],
+		"db": {"name": "pending-main", "value": {"DSN": "main"}}
+	}`)
+	var config PendingConfig
+	err := json.Unmarshal(data, &config)
+	if err == nil {
+		t.Fatal("should failed without deferred mode")
+	}
+	pending := typemap.NewPendingRefs()
+	config = PendingConfig{}
+	err = pending.Unmarshal(data, &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Len() != 5 {
+		t.Fatalf("should record 5 refs, got %d", pending.Len())
+	}
+	var other PendingConfig
+	err = json.Unmarshal([]byte(`{"missing": "pending-missing"}`), &other)
+	if err == nil {
+		t.Fatal("should failed since json.Unmarshal is not deferred")
+	}
+	if pending.Len() != 5 {
+		t.Fatalf("should not record refs of other unmarshals, got %d", pending.Len())
+	}
+	err = pending.ResolvePending(ctx)
+	me, ok := err.(*typemap.MultiError)
+	if !ok {
+		t.Fatalf("should be multi error, got %v", err)
+	}
+	keys := me.Keys()
+	if len(keys) != 1 || keys[0] != "Ref[github.com/ccmonky/typemap_test:*typemap_test.PendingDB] pending-missing" {
+		t.Fatalf("should ==, got %v", keys)
+	}
+	if !typemap.IsNotFound(err) {
+		t.Fatalf("should be not found, got %v", err)
+	}
+	if config.Main.MustValue(ctx).DSN != "main" || config.DSN.MustValue(ctx) != "main" {
+		t.Fatal("should ==")
+	}
+	err = typemap.Set(ctx, "pending-main", &PendingDB{DSN: "changed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replica := config.Replicas["r1"]
+	if replica.MustValue(ctx).DSN != "main" {
+		t.Fatal("ref in map should be primed by ResolvePending")
+	}
+	service, err := typemap.Get[*PendingService](ctx, "pending-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.DB.MustValue(ctx).DSN != "main" {
+		t.Fatal("ref in the value of Reg should be primed by ResolvePending")
+	}
+	err = pending.ResolvePending(ctx)
+	if err != nil || pending.Len() != 0 {
+		t.Fatal("should be cleared")
+	}
+}
@@ -0,0 +1,135 @@
+package typemap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Poly used as a polymorphic field of interface Iface, the concrete type is specified by the type discriminator, e.g.
+//
+//	type Config struct {
+//		Cache Poly[Cache] `json:"cache"`
+//	}
+//
+// // 1. unmarshal, type is the type id string or alias(see `WithAlias`) of a registered type which implements Cache
+// err := json.Unmarshal([]byte(`{"cache": {"type": "redis-cache", "config": {"addr": "..."}}}`), &config)
+// // or register the value as an instance of Cache like `Reg` if name specified
+// err := json.Unmarshal([]byte(`{"cache": {"type": "redis-cache", "name": "redis", "config": {...}}}`), &config)
+// // 2. use the value
+// config.Cache.Value.Get(...)
+type Poly[Iface any] struct {
+	// Type is the type id string or alias of the concrete type
+	Type string
+	// Name is the instance name of Iface used to register the value, empty means not register
+	Name string
+	// Action is the action used to register the value, available values are: ["register", "set"], default is "set"
+	Action Action
+	// Value is the value of the concrete type
+	Value Iface
+	// Options used to look up the type(e.g. `WithTypeOption(WithTypeMapName(...))`) and register the value
+	Options []Option
+}
+
+// NewPoly create a new `*Poly[Iface]` with value, the type discriminator is resolved when marshal
+func NewPoly[Iface any](value Iface, opts ...Option) *Poly[Iface] {
+	return &Poly[Iface]{
+		Value:   value,
+		Options: opts,
+	}
+}
+
+// UnmarshalJSON create the value of the concrete type specified by type discriminator, and decode the config into it,
+// if name specified then register the value as an instance of Iface, `null` leaves a zero Poly
+func (p *Poly[Iface]) UnmarshalJSON(b []byte) error {
+	return p.unmarshal(b, json.Unmarshal)
+}
+
+// unmarshalPending implements `pendingUnmarshaler`, references in the config are recorded into pending
+func (p *Poly[Iface]) unmarshalPending(pending *PendingRefs, b []byte) error {
+	return p.unmarshal(b, pending.Unmarshal)
+}
+
+func (p *Poly[Iface]) unmarshal(b []byte, unmarshal func(data []byte, v any) error) error {
+	if string(b) == "null" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultUnmarshalTimeout)
+	defer cancel()
+	options := NewOptions(p.Options...)
+	helper := &polySerdeHelper{}
+	err := json.Unmarshal(b, helper)
+	if err != nil {
+		return fmt.Errorf("unmarshal Poly[%s]: %s failed: %v", TypeIdOf[Iface](), string(b), err)
+	}
+	typ := GetTypeByID(helper.Type, options.TypeOptions...)
+	if typ == nil {
+		return NewNotFoundError(fmt.Sprintf("unmarshal Poly[%s]: type %s not found", TypeIdOf[Iface](), helper.Type))
+	}
+	n := typ.New()
+	if len(helper.Config) > 0 {
+		err = unmarshal(helper.Config, n)
+		if err != nil {
+			return fmt.Errorf("unmarshal Poly[%s]: config of %s failed: %v", TypeIdOf[Iface](), helper.Type, err)
+		}
+	}
+	value, ok := typ.Deref(n).(Iface)
+	if !ok {
+		return &TypeMismatchError{TypeId: TypeIdOf[Iface]().String(), Key: helper.Name, Value: typ.Deref(n)}
+	}
+	p.Type = helper.Type
+	p.Name = helper.Name
+	p.Action = helper.Action
+	p.Value = value
+	if p.Name == "" {
+		return nil
+	}
+	switch p.Action {
+	case RegisterAction:
+		err = Register(ctx, p.Name, p.Value, p.Options...)
+	default:
+		err = Set(ctx, p.Name, p.Value, p.Options...)
+	}
+	if err != nil {
+		return fmt.Errorf("%s Poly[%s] %s failed: %w", p.Action, TypeIdOf[Iface](), p.Name, err)
+	}
+	return nil
+}
+
+// MarshalJSON write the type discriminator and config of the value, if Type is empty,
+// use the alias(or type id string if no alias) of the value's type
+func (p Poly[Iface]) MarshalJSON() ([]byte, error) {
+	var value any = p.Value
+	if value == nil {
+		return []byte("null"), nil
+	}
+	typeId := p.Type
+	if typeId == "" {
+		typ := Types(NewOptions(p.Options...).TypeOptions...)[reflect.TypeOf(value)]
+		if typ == nil {
+			return nil, NewNotFoundError(fmt.Sprintf("marshal Poly[%s]: type %T not registered", TypeIdOf[Iface](), value))
+		}
+		typeId = typ.Alias()
+		if typeId == "" {
+			typeId = typ.String()
+		}
+	}
+	config, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(polySerdeHelper{
+		Type:   typeId,
+		Name:   p.Name,
+		Config: config,
+		Action: p.Action,
+	})
+}
+
+type polySerdeHelper struct {
+	Type   string          `json:"type"`
+	Name   string          `json:"name,omitempty"`
+	Config json.RawMessage `json:"config,omitempty"`
+	Action Action          `json:"action,omitempty"`
+}
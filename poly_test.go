@@ -0,0 +1,102 @@
+package typemap_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+type PolyCache interface {
+	Addr() string
+}
+
+type PolyRedisCache struct {
+	Address string `json:"address"`
+}
+
+func (c *PolyRedisCache) Addr() string { return c.Address }
+
+type PolyConfig struct {
+	Cache typemap.Poly[PolyCache] `json:"cache"`
+}
+
+func TestPoly(t *testing.T) {
+	typemap.MustRegisterType[*PolyRedisCache](typemap.WithAlias("poly-redis-cache"))
+	typemap.MustRegisterType[PolyCache]()
+	var config PolyConfig
+	err := json.Unmarshal([]byte(`{"cache": {"type": "poly-redis-cache", "name": "redis", "config": {"address": "127.0.0.1:6379"}}}`), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Cache.Value.Addr() != "127.0.0.1:6379" {
+		t.Fatalf("should ==, got %s", config.Cache.Value.Addr())
+	}
+	ctx := context.Background()
+	c, err := typemap.Get[PolyCache](ctx, "redis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != config.Cache.Value {
+		t.Fatal("should ==")
+	}
+	b, err := json.Marshal(typemap.NewPoly[PolyCache](&PolyRedisCache{Address: "localhost"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"poly-redis-cache","config":{"address":"localhost"}}` {
+		t.Fatalf("should ==, got %s", b)
+	}
+	var p typemap.Poly[PolyCache]
+	err = json.Unmarshal(b, &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Value.Addr() != "localhost" || p.Type != "poly-redis-cache" {
+		t.Fatal("should ==")
+	}
+	err = json.Unmarshal([]byte(`{"type": "poly-not-exist"}`), &p)
+	if !typemap.IsNotFound(err) {
+		t.Fatalf("should be not found, got %v", err)
+	}
+	typemap.MustRegisterType[PolyRedisCache]()
+	err = json.Unmarshal([]byte(`{"type": "`+typemap.TypeIdOf[PolyRedisCache]().String()+`", "config": {}}`), &p)
+	if !typemap.IsTypeMismatch(err) {
+		t.Fatalf("should be type mismatch, got %v", err)
+	}
+	b, err = json.Marshal(PolyConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var zero PolyConfig
+	err = json.Unmarshal(b, &zero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zero.Cache.Value != nil || zero.Cache.Type != "" {
+		t.Fatal("should be zero")
+	}
+	tm := typemap.WithTypeOption(typemap.WithTypeMapName("poly"))
+	typemap.MustRegisterType[*PolyRedisCache](typemap.WithTypeMapName("poly"), typemap.WithAlias("poly-redis"))
+	typemap.MustRegisterType[PolyCache](typemap.WithTypeMapName("poly"))
+	p = typemap.Poly[PolyCache]{Options: []typemap.Option{tm}}
+	err = json.Unmarshal([]byte(`{"type": "poly-redis", "name": "redis", "config": {"address": "poly"}}`), &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = typemap.Get[PolyCache](ctx, "redis", tm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Addr() != "poly" {
+		t.Fatal("should ==")
+	}
+	b, err = json.Marshal(typemap.NewPoly[PolyCache](&PolyRedisCache{Address: "poly"}, tm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"poly-redis","config":{"address":"poly"}}` {
+		t.Fatalf("should ==, got %s", b)
+	}
+}
@@ -0,0 +1,78 @@
+package typemap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/eko/gocache/lib/v4/codec"
+)
+
+// GetByPrefix returns instances of T whose key has the prefix in a stable key order,
+// the lookup is efficient if store implements `PrefixInterface`(e.g. `RadixStore`), otherwise all keys will be filtered.
+func GetByPrefix[T any](ctx context.Context, prefix string, opts ...Option) ([]Entry[T], error) {
+	options := NewOptions(opts...)
+	typ, cache, err := getInstancesCache[T](options.Tag, false, options.TypeOptions...)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := prefixKeys(ctx, typ, options.Tag, cache, prefix)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry[T]
+	for _, key := range keys {
+		v, err := storeGet(ctx, cache, key)
+		if err != nil {
+			if IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		tv, ok := v.(T)
+		if !ok {
+			return nil, &TypeMismatchError{TypeId: typ.String(), Key: key, Value: v}
+		}
+		entries = append(entries, Entry[T]{Key: key, Value: tv})
+	}
+	return entries, nil
+}
+
+// DeleteByPrefix delete instances of T whose key has the prefix
+func DeleteByPrefix[T any](ctx context.Context, prefix string, opts ...Option) error {
+	options := NewOptions(opts...)
+	typ, cache, err := getInstancesCache[T](options.Tag, false, options.TypeOptions...)
+	if err != nil {
+		return err
+	}
+	keys, err := prefixKeys(ctx, typ, options.Tag, cache, prefix)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		err = deleteInstance(ctx, typ, cache, options.Tag, key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func prefixKeys(ctx context.Context, typ *Type, tag string, cache interface {
+	GetCodec() codec.CodecInterface
+}, prefix string) ([]any, error) {
+	if pi, ok := cache.GetCodec().GetStore().(PrefixInterface); ok {
+		return pi.KeysWithPrefix(ctx, prefix)
+	}
+	keys, err := instanceKeys(ctx, typ, tag, cache)
+	if err != nil {
+		return nil, err
+	}
+	matched := keys[:0]
+	for _, key := range keys {
+		if strings.HasPrefix(fmt.Sprint(key), prefix) {
+			matched = append(matched, key)
+		}
+	}
+	return matched, nil
+}
@@ -0,0 +1,65 @@
+package typemap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+type PrefixTest struct {
+	Value string
+}
+
+type PrefixMapTest struct {
+	Value string
+}
+
+func TestGetByPrefix(t *testing.T) {
+	err := typemap.RegisterType[*PrefixTest](typemap.WithRadixStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testGetByPrefix[PrefixTest](t, func(s string) *PrefixTest { return &PrefixTest{Value: s} })
+	typ := typemap.GetType[*PrefixTest]()
+	c := typ.InstancesCache("").(*typemap.CacheAny[*PrefixTest])
+	if c.GetCodec().GetStore().GetType() != "radix" {
+		t.Errorf("should be radix store, got %s", c.GetCodec().GetStore().GetType())
+	}
+	testGetByPrefix[PrefixMapTest](t, func(s string) *PrefixMapTest { return &PrefixMapTest{Value: s} })
+}
+
+func testGetByPrefix[T any](t *testing.T, newT func(string) *T) {
+	ctx := context.Background()
+	for _, key := range []string{"tenant/b/db", "tenant/a/db", "tenant/a/cache", "tenant", "other/a"} {
+		err := typemap.Register(ctx, key, newT(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := typemap.GetByPrefix[*T](ctx, "tenant/a/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Key != "tenant/a/cache" || entries[1].Key != "tenant/a/db" {
+		t.Fatalf("%T get by prefix got %v", *new(T), entries)
+	}
+	entries, err = typemap.GetByPrefix[*T](ctx, "tenant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 || entries[0].Key != "tenant" {
+		t.Fatalf("%T get by prefix tenant got %v", *new(T), entries)
+	}
+	err = typemap.DeleteByPrefix[*T](ctx, "tenant/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := typemap.Keys[*T](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0] != "other/a" || keys[1] != "tenant" {
+		t.Fatalf("%T keys after delete by prefix got %v", *new(T), keys)
+	}
+}
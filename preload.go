@@ -0,0 +1,106 @@
+package typemap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Preload load instances of T specified by keys into instances cache, if keys is empty then use the keys specified by
+// `WithPreloadKeys`, keys are loaded concurrently if specified by `WithConcurrency`,
+// stop on the first error and returns a `*MultiError` of all the failed keys
+func Preload[T any](ctx context.Context, keys []any, opts ...Option) error {
+	options := NewOptions(opts...)
+	typ, cache, err := getInstancesCache[T](options.Tag, false, options.TypeOptions...)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		keys = typ.PreloadKeys()
+	}
+	return preload(ctx, keys, func(ctx context.Context, key any) error {
+		_, err := cache.Get(ctx, key)
+		return err
+	}, options.Concurrency)
+}
+
+// PreloadAll preload instances of all types in the TypeMap(specified by `WithTypeMapName`) in the order of type id with keys specified by `WithPreloadKeys`,
+// stop on the first error and returns a `*MultiError` of all the failed keys, the keys are prefixed with the type id,
+// e.g. `*pkg.Demo:key`
+func PreloadAll(ctx context.Context, opts ...Option) error {
+	options := NewOptions(opts...)
+	me := &MultiError{}
+	for _, typ := range sortedTypes(options.TypeOptions...) {
+		keys := typ.PreloadKeys()
+		if len(keys) == 0 {
+			continue
+		}
+		cache, ok := typ.InstancesCache(options.Tag).(SetterCacheAnyInterface)
+		if !ok {
+			me.add(typ.String(), NewNotFoundError(fmt.Sprintf("%s tag cache %s", typ.String(), options.Tag)))
+			break
+		}
+		err := preload(ctx, keys, func(ctx context.Context, key any) error {
+			_, err := cache.GetAny(ctx, key)
+			return err
+		}, options.Concurrency)
+		if err != nil {
+			var kerrs *MultiError
+			if !errors.As(err, &kerrs) {
+				return err
+			}
+			for _, ke := range kerrs.Errors {
+				me.add(fmt.Sprintf("%s:%v", typ.String(), ke.Key), ke.Err)
+			}
+			break
+		}
+	}
+	return me.errorOrNil()
+}
+
+// preload call get for keys with at most concurrency goroutines, stop on the first error,
+// errors caused by the stop are not reported
+func preload(ctx context.Context, keys []any, get func(ctx context.Context, key any) error, concurrency int) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	stopCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	me := &MultiError{}
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, key := range keys {
+		select {
+		case sem <- struct{}{}:
+		case <-stopCtx.Done():
+		}
+		if stopCtx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(key any) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			err := get(stopCtx, key)
+			if err == nil {
+				return
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			if stopCtx.Err() != nil && ctx.Err() == nil && errors.Is(err, context.Canceled) {
+				return // NOTE: stopped by other failed key
+			}
+			me.add(key, err)
+			stop()
+		}(key)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil && len(me.Errors) == 0 {
+		return err
+	}
+	return me.errorOrNil()
+}
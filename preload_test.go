@@ -0,0 +1,62 @@
+package typemap_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+var preloadLoads int32
+
+type PreloadType struct {
+	Value string
+}
+
+func (PreloadType) Load(ctx context.Context, key any) (*PreloadType, error) {
+	atomic.AddInt32(&preloadLoads, 1)
+	if key == "bad" {
+		return nil, fmt.Errorf("load %v failed", key)
+	}
+	return &PreloadType{Value: key.(string)}, nil
+}
+
+func TestPreload(t *testing.T) {
+	ctx := context.Background()
+	err := typemap.RegisterType[*PreloadType](typemap.WithPreloadKeys("a", "b", "c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.Preload[*PreloadType](ctx, nil, typemap.WithConcurrency(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&preloadLoads); n != 3 {
+		t.Fatalf("should load 3 keys, got %d", n)
+	}
+	_, err = typemap.Get[*PreloadType](ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&preloadLoads); n != 3 {
+		t.Fatalf("preloaded key should be cached, got %d loads", n)
+	}
+	err = typemap.Preload[*PreloadType](ctx, []any{"bad", "d", "e"})
+	var merr *typemap.MultiError
+	if !errors.As(err, &merr) {
+		t.Fatalf("should be multi error, got %v", err)
+	}
+	if keys := merr.Keys(); len(keys) != 1 || keys[0] != "bad" {
+		t.Fatalf("should fail on bad, got %v", keys)
+	}
+	if n := atomic.LoadInt32(&preloadLoads); n != 4 {
+		t.Fatalf("should stop on the first error, got %d loads", n)
+	}
+	err = typemap.PreloadAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
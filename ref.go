@@ -24,6 +24,8 @@ import (
 type Ref[T any] struct {
 	Name string `json:"name"`
 	ValueCache[T]
+
+	opts []Option // opts specified by `Inject`, e.g. tag
 }
 
 // NewRef create a new `*Ref[T]` and execute the reference
@@ -32,7 +34,7 @@ func NewRef[T any](name string) *Ref[T] {
 	r := Ref[T]{
 		Name: name,
 		ValueCache: ValueCache[T]{
-			lock: new(sync.RWMutex),
+			cell: new(valueCell[T]),
 		},
 	}
 	return &r
@@ -46,40 +48,66 @@ func (r *Ref[T]) SetCache(cache bool) {
 
 // Reference execute the reference the value according name
 func (r *Ref[T]) Reference(ctx context.Context) error {
-	value, err := Get[T](ctx, r.Name)
+	value, err := getConverted[T](ctx, r.Name)
 	if err != nil {
-		return fmt.Errorf("get Ref[%T] %s failed: %v", *new(T), r.Name, err)
+		return fmt.Errorf("get Ref[%T] %s failed: %w", *new(T), r.Name, err)
 	}
 	r.ValueCache.setValue(value)
 	return err
 }
 
+// inject implements `injector`, attr is ignored, opts are kept and used by `Value`
+func (r *Ref[T]) inject(ctx context.Context, name, attr string, opts ...Option) error {
+	r.Name = name
+	r.opts = opts
+	value, err := getConverted[T](ctx, r.Name, opts...)
+	if err != nil {
+		return fmt.Errorf("get Ref[%T] %s failed: %w", *new(T), r.Name, err)
+	}
+	r.ValueCache.setValue(value)
+	return nil
+}
+
 // UnmarshalJSON custom unmarshal to support simple form(just a string which is a instance name of T)
 func (r *Ref[T]) UnmarshalJSON(b []byte) error {
+	err := r.unmarshal(b)
+	if err != nil {
+		return err
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultUnmarshalTimeout)
 	defer cancel()
+	v, err := getConverted[T](ctx, r.Name)
+	if err != nil {
+		return fmt.Errorf("get Ref[%T] %s failed: %v", *new(T), string(b), err)
+	}
+	r.ValueCache.setValue(v)
+	return nil
+}
+
+// unmarshalPending implements `pendingUnmarshaler`, the reference is recorded into pending and resolved later
+func (r *Ref[T]) unmarshalPending(pending *PendingRefs, b []byte) error {
+	err := r.unmarshal(b)
+	if err != nil {
+		return err
+	}
+	r.ValueCache.getCell() // NOTE: shared with copies, e.g. value of map, so that they are primed by `ResolvePending`
+	pending.add(fmt.Sprintf("Ref[%s] %s", TypeIdOf[T](), r.Name), r.Reference)
+	return nil
+}
+
+func (r *Ref[T]) unmarshal(b []byte) error {
 	if b[0] == '"' && b[len(b)-1] == '"' { // NOTE: simple form
 		r.Name = string(b[1 : len(b)-1])
 		r.ValueCache.Cache = true // NOTE: simple form always cache
-		v, err := Get[T](ctx, r.Name)
-		if err != nil {
-			return fmt.Errorf("get Ref[%T] %s failed: %v", *new(T), string(b), err)
-		}
-		r.ValueCache.setValue(v)
-	} else { // NOTE: normal form
-		helper := &refSerdeHelper{}
-		err := json.Unmarshal(b, helper)
-		if err != nil {
-			return fmt.Errorf("unmarshal Ref[%T]: %s failed: %v", *new(T), string(b), err)
-		}
-		r.Name = helper.Name
-		r.ValueCache.Cache = helper.Cache
-		v, err := Get[T](ctx, r.Name)
-		if err != nil {
-			return fmt.Errorf("get Ref[%T] %s failed: %v", *new(T), string(b), err)
-		}
-		r.ValueCache.setValue(v)
+		return nil
 	}
+	helper := &refSerdeHelper{} // NOTE: normal form
+	err := json.Unmarshal(b, helper)
+	if err != nil {
+		return fmt.Errorf("unmarshal Ref[%T]: %s failed: %v", *new(T), string(b), err)
+	}
+	r.Name = helper.Name
+	r.ValueCache.Cache = helper.Cache
 	return nil
 }
 
@@ -105,8 +133,9 @@ func (r *Ref[T]) MustValue(ctx context.Context, opts ...Option) T {
 
 // Value returns the referenced value
 func (r *Ref[T]) Value(ctx context.Context, opts ...Option) (T, error) {
+	opts = mergeOptions(r.opts, opts)
 	load := func() (T, error) {
-		return Get[T](ctx, r.Name, opts...)
+		return getConverted[T](ctx, r.Name, opts...)
 	}
 	return r.ValueCache.Value(load)
 }
@@ -120,12 +149,33 @@ type RefAttr[T, A any] struct {
 	Name string `json:"name"`
 	Attr string `json:"attr"`
 	ValueCache[A]
+
+	opts []Option // opts specified by `Inject`, e.g. tag
 }
 
-// UnmarshalJSON custom unmarshal to support simple form(just a string which is a instance name of T)
+// UnmarshalJSON custom unmarshal
 func (ra *RefAttr[T, A]) UnmarshalJSON(b []byte) error {
+	err := ra.unmarshal(b)
+	if err != nil {
+		return err
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultUnmarshalTimeout)
 	defer cancel()
+	return ra.Reference(ctx)
+}
+
+// unmarshalPending implements `pendingUnmarshaler`, the reference is recorded into pending and resolved later
+func (ra *RefAttr[T, A]) unmarshalPending(pending *PendingRefs, b []byte) error {
+	err := ra.unmarshal(b)
+	if err != nil {
+		return err
+	}
+	ra.ValueCache.getCell() // NOTE: shared with copies, e.g. value of map, so that they are primed by `ResolvePending`
+	pending.add(fmt.Sprintf("RefAttr[%s, %s] %s.%s", TypeIdOf[T](), TypeIdOf[A](), ra.Name, ra.Attr), ra.Reference)
+	return nil
+}
+
+func (ra *RefAttr[T, A]) unmarshal(b []byte) error {
 	helper := &refSerdeHelper{}
 	err := json.Unmarshal(b, helper)
 	if err != nil {
@@ -134,13 +184,26 @@ func (ra *RefAttr[T, A]) UnmarshalJSON(b []byte) error {
 	ra.Name = helper.Name
 	ra.Attr = helper.Attr
 	ra.ValueCache.Cache = helper.Cache
-	sv, err := Get[T](ctx, ra.Name)
+	return nil
+}
+
+// Reference execute the reference the attr value according name and attr
+func (ra *RefAttr[T, A]) Reference(ctx context.Context) error {
+	return ra.inject(ctx, ra.Name, ra.Attr)
+}
+
+// inject implements `injector`, opts are kept and used by `Value`
+func (ra *RefAttr[T, A]) inject(ctx context.Context, name, attr string, opts ...Option) error {
+	ra.Name = name
+	ra.Attr = attr
+	ra.opts = opts
+	sv, err := Get[T](ctx, ra.Name, opts...)
 	if err != nil {
-		return fmt.Errorf("get RefAttr[%T, %T] struct %s failed: %v", *new(T), *new(A), ra.Name, err)
+		return fmt.Errorf("get RefAttr[%T, %T] struct %s failed: %w", *new(T), *new(A), ra.Name, err)
 	}
 	av, err := getAttr[A](sv, ra.Attr)
 	if err != nil {
-		return fmt.Errorf("get RefAttr[%T, %T] attr %s failed: %v", *new(T), *new(A), ra.Attr, err)
+		return fmt.Errorf("get RefAttr[%T, %T] attr %s failed: %w", *new(T), *new(A), ra.Attr, err)
 	}
 	ra.ValueCache.setValue(av)
 	return nil
@@ -161,6 +224,7 @@ func (ra *RefAttr[T, A]) MustValue(ctx context.Context, opts ...Option) A {
 }
 
 func (ra *RefAttr[T, A]) Value(ctx context.Context, opts ...Option) (A, error) {
+	opts = mergeOptions(ra.opts, opts)
 	load := func() (A, error) {
 		tv, err := Get[T](ctx, ra.Name, opts...)
 		if err != nil {
@@ -171,49 +235,65 @@ func (ra *RefAttr[T, A]) Value(ctx context.Context, opts ...Option) (A, error) {
 	return ra.ValueCache.Value(load)
 }
 
+// mergeOptions returns base followed by opts without modifying base
+func mergeOptions(base, opts []Option) []Option {
+	if len(base) == 0 {
+		return opts
+	}
+	return append(base[:len(base):len(base)], opts...)
+}
+
+// ValueCache caches the referenced value if Cache is true, copies of a ValueCache share the cached value
 type ValueCache[T any] struct {
 	Cache bool `json:"cache,omitempty"`
 
+	cell *valueCell[T]
+}
+
+type valueCell[T any] struct {
 	value  T
 	cached bool
-	lock   *sync.RWMutex
+	lock   sync.RWMutex
+}
+
+func (vc *ValueCache[T]) getCell() *valueCell[T] {
+	if vc.cell == nil {
+		vc.cell = new(valueCell[T])
+	}
+	return vc.cell
 }
 
 func (vc *ValueCache[T]) setValue(value T) {
 	if !vc.Cache {
 		return
 	}
-	if vc.lock == nil {
-		vc.lock = new(sync.RWMutex)
-	}
-	vc.lock.Lock()
-	defer vc.lock.Unlock()
-	vc.value = value
-	vc.cached = true
+	cell := vc.getCell()
+	cell.lock.Lock()
+	defer cell.lock.Unlock()
+	cell.value = value
+	cell.cached = true
 }
 
 func (vc *ValueCache[T]) Value(loadFunc func() (T, error)) (T, error) {
-	if vc.lock == nil {
-		vc.lock = new(sync.RWMutex)
-	}
+	cell := vc.getCell()
 	if vc.Cache {
-		vc.lock.RLock()
-		if vc.cached {
-			v := vc.value
-			vc.lock.RUnlock()
+		cell.lock.RLock()
+		if cell.cached {
+			v := cell.value
+			cell.lock.RUnlock()
 			return v, nil
 		}
-		vc.lock.RUnlock()
+		cell.lock.RUnlock()
 	}
 	v, err := loadFunc()
 	if err != nil {
 		return v, err
 	}
 	if vc.Cache {
-		vc.lock.Lock()
-		vc.cached = true
-		vc.value = v
-		vc.lock.Unlock()
+		cell.lock.Lock()
+		cell.cached = true
+		cell.value = v
+		cell.lock.Unlock()
 	}
 	return v, err
 }
@@ -226,7 +306,7 @@ func getAttr[T any](v any, attr string) (T, error) {
 	if err != nil {
 		return *new(T), err
 	}
-	return av.(T), nil
+	return convertTo[T](av)
 }
 
 /*
@@ -244,16 +324,33 @@ av, err := GetAttr(sv, "Embed.Attr")
 */
 func GetAttr(v any, attr string) (any, error) {
 	value := reflect.ValueOf(v)
+	if !value.IsValid() {
+		return nil, fmt.Errorf("v is nil")
+	}
 	typ := value.Type()
 	for ; typ.Kind() == reflect.Ptr; typ = typ.Elem() {
+		if value.IsNil() {
+			return nil, fmt.Errorf("v(after indirect...) is nil")
+		}
 		value = reflect.Indirect(value)
 	}
 	if typ.Kind() != reflect.Struct {
 		return nil, fmt.Errorf("v(after indirect...) is not struct")
 	}
+	name := attr
 	i := strings.Index(attr, ".")
+	if i >= 0 {
+		name = attr[:i]
+	}
+	field := value.FieldByName(name)
+	if !field.IsValid() {
+		return nil, fmt.Errorf("field %s not found in %s", name, typ.String())
+	}
+	if !field.CanInterface() {
+		return nil, fmt.Errorf("field %s of %s is unexported", name, typ.String())
+	}
 	if i < 0 {
-		return value.FieldByName(attr).Interface(), nil
+		return field.Interface(), nil
 	}
-	return GetAttr(value.FieldByName(attr[:i]).Interface(), attr[i+1:])
+	return GetAttr(field.Interface(), attr[i+1:])
 }
@@ -3,7 +3,9 @@ package typemap_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/ccmonky/typemap"
 	"github.com/stretchr/testify/assert"
@@ -211,6 +213,10 @@ func TestGetAttr(t *testing.T) {
 		v, err = typemap.GetAttr(obj, "EmbedPtrPtr.Slice")
 		assert.Nilf(t, err, "%T: Get EmbedPtrPtr.Slice err", obj)
 		assert.Equalf(t, v, []int{7, 8, 9}, "%T: Get EmbedPtrPtr.Slice value", obj)
+		_, err = typemap.GetAttr(obj, "NotExist")
+		assert.NotNilf(t, err, "%T: Get NotExist should err", obj)
+		_, err = typemap.GetAttr(obj, "StringPtr.Value")
+		assert.NotNilf(t, err, "%T: Get StringPtr.Value should err", obj)
 	}
 }
 
@@ -243,3 +249,78 @@ func BenchmarkGetAttr(b *testing.B) {
 		typemap.GetAttr(&as, "Embed.Slice")
 	}
 }
+
+type ConverterStruct struct {
+	Timeout string
+}
+
+type ConverterDemo struct {
+	Timeout typemap.Ref[time.Duration]                       `json:"timeout"`
+	Attr    typemap.RefAttr[*ConverterStruct, time.Duration] `json:"attr"`
+}
+
+func TestRegisterConverter(t *testing.T) {
+	ctx := context.Background()
+	typemap.RegisterConverter(time.ParseDuration)
+	err := typemap.RegisterType[time.Duration]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.SetAny(ctx, typemap.TypeIdOf[time.Duration]().String(), "set", "1s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := typemap.Get[time.Duration](ctx, "set")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, time.Second, d)
+	err = typemap.SetAny(ctx, typemap.TypeIdOf[time.Duration]().String(), "bad", "xxx")
+	if !typemap.IsTypeMismatch(err) {
+		t.Fatalf("should be type mismatch, got %v", err)
+	}
+	err = typemap.Set(ctx, "converter-timeout", "2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.Register(ctx, "converter", &ConverterStruct{Timeout: "3s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var demo ConverterDemo
+	err = json.Unmarshal([]byte(`{"timeout": "converter-timeout", "attr": {"name": "converter", "attr": "Timeout"}}`), &demo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2*time.Second, demo.Timeout.V(ctx))
+	assert.Equal(t, 3*time.Second, demo.Attr.V(ctx))
+	typemap.RegisterConverter(func(s string) (fmt.Stringer, error) {
+		return nil, nil
+	})
+	var stringer typemap.RefAttr[*ConverterStruct, fmt.Stringer]
+	err = json.Unmarshal([]byte(`{"name": "converter", "attr": "Timeout"}`), &stringer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stringer.V(ctx) != nil {
+		t.Fatal("should be nil")
+	}
+	typemap.RegisterConverter(func(s fmt.Stringer) (ConverterLabel, error) {
+		return ConverterLabel(s.String()), nil
+	})
+	err = typemap.RegisterType[ConverterLabel]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.SetAny(ctx, typemap.TypeIdOf[ConverterLabel]().String(), "label", 3*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	label, err := typemap.Get[ConverterLabel](ctx, "label")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ConverterLabel("3s"), label)
+}
+
+type ConverterLabel string
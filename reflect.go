@@ -0,0 +1,82 @@
+package typemap
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/eko/gocache/lib/v4/cache"
+	"github.com/eko/gocache/lib/v4/store"
+)
+
+// MustRegisterReflectType register typ, if error then panic
+func MustRegisterReflectType(typ reflect.Type, opts ...TypeOption) {
+	err := RegisterReflectType(typ, opts...)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegisterReflectType register a *Type of typ into global TypeMap, which is equivalent to `RegisterType[T]` where T is typ,
+// used by plugins and config-driven systems which know types only as reflect.Type, NOTE:
+// - the default instances cache is a `*ReflectCache`, which does not support loaders(`Loadable`, `Default`, DI, ...)
+// - instances can only be manipulated by *Any apis and http apis, since generic apis require cache.SetterCacheInterface[T]
+func RegisterReflectType(typ reflect.Type, opts ...TypeOption) error {
+	if typ == nil {
+		return errors.New("typemap: register nil reflect type")
+	}
+	return registerType(reflectImpl(typ), opts...)
+}
+
+// typeImpl the type specific implementations used to build a *Type, by generic T(see `genericImpl`) or
+// reflect.Type(see `reflectImpl`)
+type typeImpl struct {
+	typeId   reflect.Type
+	new      func() any
+	deref    func(v any) any
+	zero     func() any
+	newCache func(opts ...TypeOption) any
+	isCache  func(c any) bool
+}
+
+func genericImpl[T any]() *typeImpl {
+	return &typeImpl{
+		typeId:   TypeOf[T](),
+		new:      func() any { return New[T]() },
+		deref:    func(n any) any { p := n.(*T); return *p },
+		zero:     func() any { return Zero[T]() },
+		newCache: func(opts ...TypeOption) any { return NewDefaultCache[T](opts...) },
+		isCache: func(c any) bool {
+			_, ok := c.(cache.SetterCacheInterface[T])
+			return ok
+		},
+	}
+}
+
+func reflectImpl(typ reflect.Type) *typeImpl {
+	return &typeImpl{
+		typeId: typ,
+		new:    func() any { return newReflect(typ).Interface() },
+		deref:  func(n any) any { return reflect.ValueOf(n).Elem().Interface() },
+		zero:   func() any { return newReflect(typ).Elem().Interface() },
+		newCache: func(opts ...TypeOption) any {
+			newStore := NewTypeOptions(opts...).NewStore
+			if newStore == nil {
+				newStore = func() store.StoreInterface { return NewMap() }
+			}
+			return NewReflectCache(typ, newStore())
+		},
+		isCache: func(c any) bool {
+			rc, ok := c.(*ReflectCache)
+			return ok && rc.ReflectType() == typ
+		},
+	}
+}
+
+// newReflect create a new pointer to typ's instance like `New`, which will indirect reflect.Ptr recursively
+func newReflect(typ reflect.Type) reflect.Value {
+	p := reflect.New(typ)
+	for v := p.Elem(); v.Kind() == reflect.Ptr; v = v.Elem() {
+		v.Set(reflect.New(v.Type().Elem()))
+	}
+	return p
+}
@@ -0,0 +1,73 @@
+package typemap_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+type ReflectTest struct {
+	Int int `json:"int"`
+}
+
+func (ReflectTest) Description() string {
+	return "reflect test"
+}
+
+func TestRegisterReflectType(t *testing.T) {
+	ctx := context.Background()
+	rt := reflect.TypeOf(&ReflectTest{})
+	err := typemap.RegisterReflectType(rt, typemap.WithAlias("reflect-test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := typemap.RegisterReflectType(nil); err == nil {
+		t.Fatal("should failed for nil reflect type")
+	}
+	typ := typemap.GetTypeByID("reflect-test")
+	if typ == nil || typ.TypeId() != rt {
+		t.Fatalf("should get reflect type, got %v", typ)
+	}
+	if typ.Description() != "reflect test" {
+		t.Fatalf("should use description of type, got %s", typ.Description())
+	}
+	if n, ok := typ.New().(**ReflectTest); !ok || *n == nil {
+		t.Fatalf("new should return non-nil **ReflectTest, got %#v", typ.New())
+	}
+	err = typemap.SetAny(ctx, "reflect-test", "a", &ReflectTest{Int: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.SetAny(ctx, "reflect-test", "b", ReflectTest{Int: 2})
+	if !typemap.IsTypeMismatch(err) {
+		t.Fatalf("should be type mismatch, got %v", err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(typemap.SetAPI))
+	defer ts.Close()
+	rp, err := http.Post(ts.URL, "application/json", bytes.NewReader([]byte(`[{"type_id": "reflect-test", "name": "c", "value": {"int": 3}}]`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp.Body.Close()
+	if rp.StatusCode != http.StatusOK {
+		t.Fatalf("should set by http api, got %d", rp.StatusCode)
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		v, err := typemap.GetAny(ctx, "reflect-test", key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.(*ReflectTest).Int != want {
+			t.Fatalf("%s should == %d, got %d", key, want, v.(*ReflectTest).Int)
+		}
+	}
+	_, err = typemap.Get[*ReflectTest](ctx, "a")
+	if !typemap.IsCacheType(err) {
+		t.Fatalf("generic api should not be supported, got %v", err)
+	}
+}
@@ -61,15 +61,30 @@ func (r *Reg[T]) Register(ctx context.Context) error {
 
 // UnmarshalJSON custom unmarshal to support automatic register T's intance into typemamp
 func (r *Reg[T]) UnmarshalJSON(b []byte) error {
+	return r.unmarshal(b, json.Unmarshal)
+}
+
+// unmarshalPending implements `pendingUnmarshaler`, the value is registered immediately while references in it are recorded into pending
+func (r *Reg[T]) unmarshalPending(pending *PendingRefs, b []byte) error {
+	return r.unmarshal(b, pending.Unmarshal)
+}
+
+func (r *Reg[T]) unmarshal(b []byte, unmarshal func(data []byte, v any) error) error {
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultUnmarshalTimeout)
 	defer cancel()
-	helper := &regSerdeHelper[T]{}
+	helper := &regSerdeHelper{}
 	err := json.Unmarshal(b, helper)
 	if err != nil {
 		return fmt.Errorf("unmarshal Reg[%T]: %s failed: %v", *new(T), string(b), err)
 	}
 	r.Name = helper.Name
-	r.Value = helper.Value
+	r.Value = Zero[T]()
+	if len(helper.Value) > 0 {
+		err = unmarshal(helper.Value, &r.Value)
+		if err != nil {
+			return fmt.Errorf("unmarshal Reg[%T]: %s failed: %v", *new(T), string(b), err)
+		}
+	}
 	var action = r.Action
 	if helper.Action == RegisterAction || helper.Action == SetAction {
 		action = helper.Action
@@ -99,10 +114,10 @@ func (r Reg[T]) MarshalSchema() ([]byte, error) {
 	}`, r.Name)), nil
 }
 
-type regSerdeHelper[T any] struct {
-	Name   string `json:"name"`
-	Value  T      `json:"value"`
-	Action Action `json:"action,omitempty"`
+type regSerdeHelper struct {
+	Name   string          `json:"name"`
+	Value  json.RawMessage `json:"value"`
+	Action Action          `json:"action,omitempty"`
 }
 
 func (r Reg[T]) CurrentValue(ctx context.Context, opts ...Option) (T, error) {
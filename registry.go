@@ -0,0 +1,138 @@
+package typemap
+
+import (
+	"context"
+	"sync"
+
+	"github.com/eko/gocache/lib/v4/cache"
+	"github.com/eko/gocache/lib/v4/store"
+)
+
+// MustHandle returns a *Registry[T], if error then panic
+func MustHandle[T any](opts ...Option) *Registry[T] {
+	r, err := Handle[T](opts...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Handle returns a *Registry[T] bound to the TypeMap(specified by `WithTypeMapName`) and tag(specified by `WithTag`),
+// T's instances cache is resolved once here, if T not found, the default will be registered.
+// NOTE: the handle will not follow the changes of `SetType` or `RegisterType` with new tag cache after creation
+func Handle[T any](opts ...Option) (*Registry[T], error) {
+	options := NewOptions(opts...)
+	typ, cache, err := getInstancesCache[T](options.Tag, true, options.TypeOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return &Registry[T]{
+		typ:          typ,
+		cache:        cache,
+		tag:          options.Tag,
+		storeOptions: options.StoreOptions,
+	}, nil
+}
+
+// Registry a typed handle of T's instances with a specified tag,
+// which provides the method-based apis that `TypeMap` can not offer(`method must have no type parameters`)
+type Registry[T any] struct {
+	typ          *Type
+	cache        cache.SetterCacheInterface[T]
+	tag          string
+	storeOptions []store.Option
+}
+
+// Type returns the *Type of T
+func (r *Registry[T]) Type() *Type {
+	return r.typ
+}
+
+// Tag returns the bound tag
+func (r *Registry[T]) Tag() string {
+	return r.tag
+}
+
+// Get get instance of T
+func (r *Registry[T]) Get(ctx context.Context, key any) (T, error) {
+	return r.cache.Get(ctx, key)
+}
+
+// Set set a T instance, if exists then override it,
+// if no store options specified then use the store options given by `Handle`
+func (r *Registry[T]) Set(ctx context.Context, key any, object T, opts ...store.Option) error {
+	if len(opts) == 0 {
+		opts = r.storeOptions
+	}
+	return setInstance[T](ctx, r.typ, r.cache, r.tag, key, object, opts...)
+}
+
+// Register register a T instance, if exists return error
+// if no store options specified then use the store options given by `Handle`
+func (r *Registry[T]) Register(ctx context.Context, key any, object T, opts ...store.Option) error {
+	if len(opts) == 0 {
+		opts = r.storeOptions
+	}
+	return registerInstance[T](ctx, r.typ, r.cache, r.tag, key, object, opts...)
+}
+
+// Delete delete a T instance specified by key
+func (r *Registry[T]) Delete(ctx context.Context, key any) error {
+	return deleteInstance(ctx, r.typ, r.cache, r.tag, key)
+}
+
+// Keys returns all keys of T's instances in a stable order
+func (r *Registry[T]) Keys(ctx context.Context) ([]any, error) {
+	return instanceKeys(ctx, r.typ, r.tag, r.cache)
+}
+
+// All returns all T's instances
+func (r *Registry[T]) All(ctx context.Context) (map[any]T, error) {
+	m, err := instanceAll(ctx, r.typ, r.tag, r.cache)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[any]T, len(m))
+	for k, v := range m {
+		result[k] = v.(T)
+	}
+	return result, nil
+}
+
+// Watch call fn on every Register|Set|Delete|Clear of T's instances with the bound tag(no matter through the
+// registry or the package level apis), until ctx is done or the returned unwatch func called.
+// fn is called synchronously, so it should not block.
+func (r *Registry[T]) Watch(ctx context.Context, fn func(event Event[T])) (unwatch func()) {
+	remove := r.typ.watch(func(event Event[any]) {
+		if event.Tag != r.tag {
+			return
+		}
+		e := Event[T]{
+			Operation: event.Operation,
+			Tag:       event.Tag,
+			Key:       event.Key,
+		}
+		if v, ok := event.Value.(T); ok {
+			e.Value = v
+		}
+		fn(e)
+	})
+	stop := make(chan struct{})
+	var once sync.Once
+	unwatch = func() {
+		once.Do(func() {
+			close(stop)
+			remove()
+		})
+	}
+	if done := ctx.Done(); done != nil { // NOTE: context.Background() is never done
+		go func() {
+			select {
+			case <-done:
+				unwatch()
+			case <-stop:
+			}
+		}()
+	}
+	return unwatch
+}
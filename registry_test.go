@@ -0,0 +1,146 @@
+package typemap_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+type RegistryTest struct {
+	Value string
+}
+
+func TestRegistry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := typemap.MustHandle[*RegistryTest](typemap.WithTag(""))
+	if r.Type() != typemap.GetType[*RegistryTest]() {
+		t.Fatal("should ==")
+	}
+	var lock sync.Mutex
+	var events []typemap.Event[*RegistryTest]
+	r.Watch(ctx, func(event typemap.Event[*RegistryTest]) {
+		lock.Lock()
+		defer lock.Unlock()
+		events = append(events, event)
+	})
+	err := r.Register(ctx, "b", &RegistryTest{Value: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.Register(ctx, "b", &RegistryTest{Value: "b"})
+	if err == nil {
+		t.Fatal("register b again should error")
+	}
+	err = r.Set(ctx, "a", &RegistryTest{Value: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.Set(ctx, "c", &RegistryTest{Value: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := r.Get(ctx, "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Value != "c" {
+		t.Fatalf("should == c, got %s", v.Value)
+	}
+	keys, err := r.Keys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 || keys[0] != "a" || keys[1] != "b" || keys[2] != "c" {
+		t.Fatalf("keys should == [a b c], got %v", keys)
+	}
+	all, err := r.All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || all["a"].Value != "a" {
+		t.Fatalf("all got %v", all)
+	}
+	err = r.Delete(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.Get(ctx, "a")
+	if !typemap.IsNotFound(err) {
+		t.Fatalf("should not found, got %v", err)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if len(events) != 4 {
+		t.Fatalf("should got 4 events, got %d", len(events))
+	}
+	for i, op := range []typemap.Operation{
+		typemap.RegisterOperation,
+		typemap.SetOperation,
+		typemap.SetOperation,
+		typemap.DeleteOperation,
+	} {
+		if events[i].Operation != op {
+			t.Errorf("event %d should be %s, got %s", i, op, events[i].Operation)
+		}
+	}
+	if events[2].Key != "c" || events[2].Value.Value != "c" {
+		t.Errorf("event 2 got %v", events[2])
+	}
+}
+
+func TestRegistryWithTag(t *testing.T) {
+	ctx := context.Background()
+	err := typemap.RegisterType[*RegistryTest](typemap.WithInstancesCache[*RegistryTest]("registry", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := typemap.Handle[*RegistryTest](typemap.WithTag("registry"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events int
+	unwatch := r.Watch(ctx, func(event typemap.Event[*RegistryTest]) {
+		events++
+	})
+	err = r.Set(ctx, "x", &RegistryTest{Value: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unwatch()
+	unwatch()
+	err = r.Set(ctx, "y", &RegistryTest{Value: "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events != 1 {
+		t.Fatalf("should stop watching after unwatch, got %d events", events)
+	}
+	v, err := typemap.Get[*RegistryTest](ctx, "x", typemap.WithTag("registry"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Value != "x" {
+		t.Fatal("should ==")
+	}
+	_, err = typemap.Get[*RegistryTest](ctx, "x")
+	if !typemap.IsNotFound(err) {
+		t.Fatalf("should not found with default tag, got %v", err)
+	}
+}
+
+func BenchmarkRegistryGet(b *testing.B) {
+	ctx := context.Background()
+	r := typemap.MustHandle[*GetBench]()
+	err := r.Set(ctx, "bench", &GetBench{Value: "bench"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_, _ = r.Get(ctx, "bench")
+	}
+}
@@ -0,0 +1,86 @@
+package typemap
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// flightGroup deduplicates concurrent loads of the same key, the load runs once and all waiters share the result
+type flightGroup[T any] struct {
+	calls map[any]*flightCall[T]
+	mu    sync.Mutex
+}
+
+type flightCall[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// do run fn for key if no in-flight call of key, otherwise wait for the in-flight call,
+// fn runs with a context detached from the cancellation of ctx, so that a canceled waiter will not affect others,
+// while each waiter returns `ctx.Err()` when its ctx is done
+func (g *flightGroup[T]) do(ctx context.Context, key any, fn func(ctx context.Context) (T, error)) (T, error) {
+	if !isComparableKey(key) {
+		return fn(ctx)
+	}
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[any]*flightCall[T])
+	}
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall[T]{
+			done: make(chan struct{}),
+		}
+		g.calls[key] = call
+		g.mu.Unlock()
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					call.err = fmt.Errorf("typemap: load %v panic: %v", key, r)
+				}
+				g.mu.Lock()
+				delete(g.calls, key)
+				g.mu.Unlock()
+				close(call.done)
+			}()
+			call.value, call.err = fn(detachedContext{ctx})
+		}()
+	} else {
+		g.mu.Unlock()
+	}
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		return *new(T), ctx.Err()
+	}
+}
+
+// detachedContext keeps the values of parent but never be canceled
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+func (c detachedContext) Value(key any) any         { return c.parent.Value(key) }
+
+// isComparableKey reports whether key can be used as a map key without panic, NOTE: `reflect.Type.Comparable` is not enough
+// since structs or arrays with interface fields may hold non-comparable dynamic values, e.g. struct{ V any }{V: []int{}}
+func isComparableKey(key any) (ok bool) {
+	if key == nil || !reflect.TypeOf(key).Comparable() {
+		return false
+	}
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return key == key // NOTE: panics if the dynamic value is not comparable
+}
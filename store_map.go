@@ -52,6 +52,30 @@ func (s *MapStore) GetAll(_ context.Context) (map[any]any, error) {
 	return itemsCopy, nil
 }
 
+// Keys returns all keys in the store
+func (s *MapStore) Keys(_ context.Context) ([]any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]any, 0, len(s.items))
+	for k := range s.items {
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// GetMany returns data stored from given keys, missing keys are absent in result
+func (s *MapStore) GetMany(_ context.Context, keys []any) (map[any]any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m := make(map[any]any, len(keys))
+	for _, key := range keys {
+		if value, ok := s.items[key.(string)]; ok {
+			m[key] = value
+		}
+	}
+	return m, nil
+}
+
 // GetWithTTL returns data stored from a given key and its corresponding TTL
 func (s *MapStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
 	value, err := s.Get(ctx, key)
@@ -63,7 +87,7 @@ func (s *MapStore) Register(ctx context.Context, key any, value any, options ...
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if _, ok := s.items[key.(string)]; ok {
-		return fmt.Errorf("mapstore: register key %v failed: alreasy exists", key)
+		return &AlreadyExistsError{Key: key}
 	}
 	s.items[key.(string)] = value
 	return nil
@@ -106,5 +130,7 @@ func (s *MapStore) Clear(_ context.Context) error {
 var (
 	_ store.StoreInterface = (*MapStore)(nil)
 	_ GetAllInterface      = (*MapStore)(nil)
+	_ GetManyInterface     = (*MapStore)(nil)
+	_ KeysInterface        = (*MapStore)(nil)
 	_ Registerable         = (*MapStore)(nil)
 )
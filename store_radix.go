@@ -0,0 +1,280 @@
+package typemap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eko/gocache/lib/v4/store"
+)
+
+const (
+	// RadixType represents the storage type as a string value
+	RadixType = "radix"
+	// RadixTagPattern represents the tag pattern to be used as a key in specified storage
+	RadixTagPattern = "radix_tag_%s"
+)
+
+// RadixStore is an ordered store based on radix tree, which keeps keys in lexicographical order,
+// and supports efficient lookups by key prefix, e.g. `GET:/typemap/` or `tenant/a/`
+type RadixStore struct {
+	root *radixNode
+	mu   sync.RWMutex
+}
+
+// NewRadix creates a new ordered store based on radix tree
+func NewRadix(options ...store.Option) *RadixStore {
+	return &RadixStore{
+		root: &radixNode{},
+	}
+}
+
+// Get returns data stored from a given key
+func (s *RadixStore) Get(_ context.Context, key any) (any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, exists := s.root.get(key.(string))
+	if !exists {
+		return nil, store.NotFoundWithCause(fmt.Errorf("%v not found in Radix store", key))
+	}
+	return value, nil
+}
+
+// GetAll returns all data stored
+func (s *RadixStore) GetAll(_ context.Context) (map[any]any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	itemsCopy := make(map[any]any)
+	s.root.walk("", func(key string, value any) bool {
+		itemsCopy[key] = value
+		return true
+	})
+	return itemsCopy, nil
+}
+
+// Keys returns all keys in lexicographical order
+func (s *RadixStore) Keys(ctx context.Context) ([]any, error) {
+	return s.KeysWithPrefix(ctx, "")
+}
+
+// KeysWithPrefix returns keys which have the prefix in lexicographical order
+func (s *RadixStore) KeysWithPrefix(_ context.Context, prefix string) ([]any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var keys []any
+	s.root.walkPrefix(prefix, func(key string, _ any) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys, nil
+}
+
+// GetMany returns data stored from given keys, missing keys are absent in result
+func (s *RadixStore) GetMany(_ context.Context, keys []any) (map[any]any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m := make(map[any]any, len(keys))
+	for _, key := range keys {
+		if value, ok := s.root.get(key.(string)); ok {
+			m[key] = value
+		}
+	}
+	return m, nil
+}
+
+// GetWithTTL returns data stored from a given key and its corresponding TTL
+func (s *RadixStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
+	value, err := s.Get(ctx, key)
+	return value, NoExpiration, err
+}
+
+// Register Set only when key not found
+func (s *RadixStore) Register(ctx context.Context, key any, value any, options ...store.Option) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.root.get(key.(string)); exists {
+		return &AlreadyExistsError{Key: key}
+	}
+	s.root.insert(key.(string), value)
+	return nil
+}
+
+// Set defines data in radix tree for given key identifier
+func (s *RadixStore) Set(ctx context.Context, key any, value any, options ...store.Option) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.root.insert(key.(string), value)
+	return nil
+}
+
+// Delete removes data in radix tree for given key identifier
+func (s *RadixStore) Delete(_ context.Context, key any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.root.delete(key.(string))
+	return nil
+}
+
+// Invalidate invalidates some cache data in radix tree for given options
+func (s *RadixStore) Invalidate(ctx context.Context, options ...store.InvalidateOption) error {
+	return nil
+}
+
+// GetType returns the store type
+func (s *RadixStore) GetType() string {
+	return RadixType
+}
+
+// Clear resets all data in the store
+func (s *RadixStore) Clear(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.root = &radixNode{}
+	return nil
+}
+
+var (
+	_ store.StoreInterface = (*RadixStore)(nil)
+	_ GetAllInterface      = (*RadixStore)(nil)
+	_ GetManyInterface     = (*RadixStore)(nil)
+	_ Registerable         = (*RadixStore)(nil)
+	_ KeysInterface        = (*RadixStore)(nil)
+	_ PrefixInterface      = (*RadixStore)(nil)
+)
+
+// radixNode node of radix tree, children are sorted by the first byte of their prefix
+type radixNode struct {
+	prefix   string
+	children []*radixNode
+	leaf     bool
+	value    any
+}
+
+// child returns the index and child whose prefix starts with c, or the index to insert if not found
+func (n *radixNode) child(c byte) (int, *radixNode) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].prefix[0] >= c
+	})
+	if i < len(n.children) && n.children[i].prefix[0] == c {
+		return i, n.children[i]
+	}
+	return i, nil
+}
+
+// insert insert key(relative to n) with value
+func (n *radixNode) insert(key string, value any) {
+	if key == "" {
+		n.leaf = true
+		n.value = value
+		return
+	}
+	i, child := n.child(key[0])
+	if child == nil {
+		n.children = append(n.children, nil)
+		copy(n.children[i+1:], n.children[i:])
+		n.children[i] = &radixNode{prefix: key, leaf: true, value: value}
+		return
+	}
+	common := commonPrefixLen(key, child.prefix)
+	if common == len(child.prefix) {
+		child.insert(key[common:], value)
+		return
+	}
+	split := &radixNode{
+		prefix:   child.prefix[:common],
+		children: []*radixNode{child},
+	}
+	child.prefix = child.prefix[common:]
+	n.children[i] = split
+	split.insert(key[common:], value)
+}
+
+func (n *radixNode) get(key string) (any, bool) {
+	for {
+		if key == "" {
+			return n.value, n.leaf
+		}
+		_, child := n.child(key[0])
+		if child == nil || !strings.HasPrefix(key, child.prefix) {
+			return nil, false
+		}
+		key = key[len(child.prefix):]
+		n = child
+	}
+}
+
+// delete delete key(relative to n), and merge the nodes which are no longer necessary
+func (n *radixNode) delete(key string) bool {
+	if key == "" {
+		if !n.leaf {
+			return false
+		}
+		n.leaf = false
+		n.value = nil
+		return true
+	}
+	i, child := n.child(key[0])
+	if child == nil || !strings.HasPrefix(key, child.prefix) {
+		return false
+	}
+	if !child.delete(key[len(child.prefix):]) {
+		return false
+	}
+	if !child.leaf {
+		switch len(child.children) {
+		case 0:
+			n.children = append(n.children[:i], n.children[i+1:]...)
+		case 1:
+			grandchild := child.children[0]
+			grandchild.prefix = child.prefix + grandchild.prefix
+			n.children[i] = grandchild
+		}
+	}
+	return true
+}
+
+// walk call fn for n and its descendants in lexicographical order, path is the full key of n
+func (n *radixNode) walk(path string, fn func(key string, value any) bool) bool {
+	if n.leaf && !fn(path, n.value) {
+		return false
+	}
+	for _, child := range n.children {
+		if !child.walk(path+child.prefix, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// walkPrefix call fn for keys which have the prefix in lexicographical order
+func (n *radixNode) walkPrefix(prefix string, fn func(key string, value any) bool) {
+	var path string
+	for prefix != "" {
+		_, child := n.child(prefix[0])
+		if child == nil {
+			return
+		}
+		switch {
+		case strings.HasPrefix(prefix, child.prefix):
+			path += child.prefix
+			prefix = prefix[len(child.prefix):]
+			n = child
+		case strings.HasPrefix(child.prefix, prefix):
+			child.walk(path+child.prefix, fn)
+			return
+		default:
+			return
+		}
+	}
+	n.walk(path, fn)
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for ; i < len(a) && i < len(b) && a[i] == b[i]; i++ {
+	}
+	return i
+}
@@ -0,0 +1,94 @@
+package typemap_test
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+func TestRadixStore(t *testing.T) {
+	ctx := context.Background()
+	s := typemap.NewRadix()
+	expect := make(map[string]int)
+	r := rand.New(rand.NewSource(1))
+	alphabet := "ab/:"
+	randKey := func() string {
+		b := make([]byte, r.Intn(6))
+		for i := range b {
+			b[i] = alphabet[r.Intn(len(alphabet))]
+		}
+		return string(b)
+	}
+	for i := 0; i < 5000; i++ {
+		key := randKey()
+		if r.Intn(3) == 0 {
+			delete(expect, key)
+			s.Delete(ctx, key)
+		} else {
+			expect[key] = i
+			s.Set(ctx, key, i)
+		}
+	}
+	for i := 0; i < 500; i++ {
+		key := randKey()
+		v, err := s.Get(ctx, key)
+		if ev, ok := expect[key]; ok {
+			if err != nil || v != ev {
+				t.Fatalf("get %q should == %d, got %v, %v", key, ev, v, err)
+			}
+		} else if !typemap.IsNotFound(err) {
+			t.Fatalf("get %q should not found, got %v, %v", key, v, err)
+		}
+	}
+	for _, prefix := range []string{"", "a", "ab", "b/", "a:b", "zzz"} {
+		var want []string
+		for key := range expect {
+			if strings.HasPrefix(key, prefix) {
+				want = append(want, key)
+			}
+		}
+		sort.Strings(want)
+		got, err := s.KeysWithPrefix(ctx, prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("prefix %q should got %d keys, got %d", prefix, len(want), len(got))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("prefix %q key %d should == %q, got %q", prefix, i, want[i], got[i])
+			}
+		}
+	}
+	all, err := s.GetAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != len(expect) {
+		t.Fatalf("GetAll should got %d, got %d", len(expect), len(all))
+	}
+	err = s.Register(ctx, "new-key", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Register(ctx, "new-key", 2)
+	if err == nil {
+		t.Fatal("register new-key again should error")
+	}
+	err = s.Clear(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := s.Keys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("should be empty after clear, got %v", keys)
+	}
+}
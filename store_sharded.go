@@ -0,0 +1,186 @@
+package typemap
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/eko/gocache/lib/v4/store"
+)
+
+const (
+	// ShardedMapType represents the storage type as a string value
+	ShardedMapType = "sharded_map"
+	// ShardedMapTagPattern represents the tag pattern to be used as a key in specified storage
+	ShardedMapTagPattern = "sharded_map_tag_%s"
+
+	// DefaultShards default number of shards used by `NewShardedMap` if shards <= 0
+	DefaultShards = 32
+)
+
+// ShardedMapStore is a store for map (memory) library, which splits items into N hashed shards,
+// each guarded by its own lock, to reduce lock contention under heavy read/write loads
+type ShardedMapStore struct {
+	shards []*mapShard
+}
+
+type mapShard struct {
+	items map[string]any
+	mu    sync.RWMutex
+}
+
+// NewShardedMap creates a new sharded store to map (memory) library instance, if shards <= 0 then use `DefaultShards`
+func NewShardedMap(shards int, options ...store.Option) *ShardedMapStore {
+	if shards <= 0 {
+		shards = DefaultShards
+	}
+	s := &ShardedMapStore{
+		shards: make([]*mapShard, shards),
+	}
+	for i := range s.shards {
+		s.shards[i] = &mapShard{
+			items: make(map[string]any),
+		}
+	}
+	return s
+}
+
+func (s *ShardedMapStore) shard(key string) *mapShard {
+	return s.shards[fnv32a(key)%uint32(len(s.shards))]
+}
+
+// fnv32a inline FNV-1a hash, avoid allocations of `hash/fnv`
+func fnv32a(key string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	h := uint32(offset32)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= prime32
+	}
+	return h
+}
+
+// Get returns data stored from a given key
+func (s *ShardedMapStore) Get(_ context.Context, key any) (any, error) {
+	var err error
+	keyStr := key.(string)
+	shard := s.shard(keyStr)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	value, exists := shard.items[keyStr]
+	if !exists {
+		err = store.NotFoundWithCause(fmt.Errorf("%v not found in ShardedMap store", key))
+	}
+	return value, err
+}
+
+func (s *ShardedMapStore) GetAll(_ context.Context) (map[any]any, error) {
+	itemsCopy := make(map[any]any)
+	for _, shard := range s.shards {
+		shard.mu.RLock()
+		for k, v := range shard.items {
+			itemsCopy[k] = v
+		}
+		shard.mu.RUnlock()
+	}
+	return itemsCopy, nil
+}
+
+// Keys returns all keys in the store
+func (s *ShardedMapStore) Keys(_ context.Context) ([]any, error) {
+	var keys []any
+	for _, shard := range s.shards {
+		shard.mu.RLock()
+		for k := range shard.items {
+			keys = append(keys, k)
+		}
+		shard.mu.RUnlock()
+	}
+	return keys, nil
+}
+
+// GetMany returns data stored from given keys, missing keys are absent in result
+func (s *ShardedMapStore) GetMany(_ context.Context, keys []any) (map[any]any, error) {
+	m := make(map[any]any, len(keys))
+	for _, key := range keys {
+		keyStr := key.(string)
+		shard := s.shard(keyStr)
+		shard.mu.RLock()
+		if value, ok := shard.items[keyStr]; ok {
+			m[key] = value
+		}
+		shard.mu.RUnlock()
+	}
+	return m, nil
+}
+
+// GetWithTTL returns data stored from a given key and its corresponding TTL
+func (s *ShardedMapStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
+	value, err := s.Get(ctx, key)
+	return value, NoExpiration, err
+}
+
+// Register Set only when key not found
+func (s *ShardedMapStore) Register(ctx context.Context, key any, value any, options ...store.Option) error {
+	keyStr := key.(string)
+	shard := s.shard(keyStr)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if _, ok := shard.items[keyStr]; ok {
+		return &AlreadyExistsError{Key: key}
+	}
+	shard.items[keyStr] = value
+	return nil
+}
+
+// Set defines data in GoCache memoey cache for given key identifier
+func (s *ShardedMapStore) Set(ctx context.Context, key any, value any, options ...store.Option) error {
+	keyStr := key.(string)
+	shard := s.shard(keyStr)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	shard.items[keyStr] = value
+	return nil
+}
+
+// Delete removes data in GoCache memoey cache for given key identifier
+func (s *ShardedMapStore) Delete(_ context.Context, key any) error {
+	keyStr := key.(string)
+	shard := s.shard(keyStr)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	delete(shard.items, keyStr)
+	return nil
+}
+
+// Invalidate invalidates some cache data in GoCache memoey cache for given options
+func (s *ShardedMapStore) Invalidate(ctx context.Context, options ...store.InvalidateOption) error {
+	return nil
+}
+
+// GetType returns the store type
+func (s *ShardedMapStore) GetType() string {
+	return ShardedMapType
+}
+
+// Clear resets all data in the store
+func (s *ShardedMapStore) Clear(_ context.Context) error {
+	for _, shard := range s.shards {
+		shard.mu.Lock()
+		shard.items = make(map[string]any)
+		shard.mu.Unlock()
+	}
+	return nil
+}
+
+var (
+	_ store.StoreInterface = (*ShardedMapStore)(nil)
+	_ GetAllInterface      = (*ShardedMapStore)(nil)
+	_ GetManyInterface     = (*ShardedMapStore)(nil)
+	_ KeysInterface        = (*ShardedMapStore)(nil)
+	_ Registerable         = (*ShardedMapStore)(nil)
+)
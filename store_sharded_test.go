@@ -0,0 +1,115 @@
+package typemap_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+	"github.com/eko/gocache/lib/v4/store"
+)
+
+type StructShardedMap struct {
+	Abc string
+}
+
+func (ssm StructShardedMap) Value() string {
+	return ssm.Abc
+}
+
+func TestShardedMapStore(t *testing.T) {
+	options := &typemap.Options{
+		TypeOptions: []typemap.TypeOption{
+			typemap.WithShardedMapStore(8),
+		},
+	}
+	first := &StructShardedMap{Abc: "abc"}
+	second := &StructShardedMap{Abc: "def"}
+	testTypeMap[*StructShardedMap](t, first, second, options)
+	typ := typemap.GetType[*StructShardedMap]()
+	assertNotNil(t, typ, "GetType of *StructShardedMap should not nil")
+	c := typ.InstancesCache("").(*typemap.CacheAny[*StructShardedMap])
+	if c.GetCodec().GetStore().GetType() != "sharded_map" {
+		t.Errorf("should be sharded map store, got %s", c.GetCodec().GetStore().GetType())
+	}
+	m, err := typemap.GetAll[*StructShardedMap](context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 2 {
+		t.Fatalf("should == 2, got %d", len(m))
+	}
+	if m["first"].Abc != "abc" || m["second"].Abc != "def" {
+		t.Fatalf("GetAll got %v", m)
+	}
+	s := typemap.NewShardedMap(0)
+	err = s.Register(context.Background(), "x", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Register(context.Background(), "x", 2)
+	if err == nil {
+		t.Fatal("register x again should error")
+	}
+	err = s.Clear(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Get(context.Background(), "x")
+	if !typemap.IsNotFound(err) {
+		t.Fatalf("should not found after clear, got %v", err)
+	}
+}
+
+var benchKeys = func() []string {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+	return keys
+}()
+
+// benchmarkStoreMixed run parallel loads which write once every `writeEvery` operations
+func benchmarkStoreMixed(b *testing.B, s store.StoreInterface, writeEvery int) {
+	ctx := context.Background()
+	for _, key := range benchKeys {
+		_ = s.Set(ctx, key, key)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var n int
+		for pb.Next() {
+			key := benchKeys[n%len(benchKeys)]
+			if n%writeEvery == 0 {
+				_ = s.Set(ctx, key, key)
+			} else {
+				_, _ = s.Get(ctx, key)
+			}
+			n++
+		}
+	})
+}
+
+func BenchmarkMapStoreReadHeavy(b *testing.B) {
+	benchmarkStoreMixed(b, typemap.NewMap(), 100)
+}
+
+func BenchmarkSyncMapStoreReadHeavy(b *testing.B) {
+	benchmarkStoreMixed(b, typemap.NewSyncMap(), 100)
+}
+
+func BenchmarkShardedMapStoreReadHeavy(b *testing.B) {
+	benchmarkStoreMixed(b, typemap.NewShardedMap(typemap.DefaultShards), 100)
+}
+
+func BenchmarkMapStoreWriteHeavy(b *testing.B) {
+	benchmarkStoreMixed(b, typemap.NewMap(), 4)
+}
+
+func BenchmarkSyncMapStoreWriteHeavy(b *testing.B) {
+	benchmarkStoreMixed(b, typemap.NewSyncMap(), 4)
+}
+
+func BenchmarkShardedMapStoreWriteHeavy(b *testing.B) {
+	benchmarkStoreMixed(b, typemap.NewShardedMap(typemap.DefaultShards), 4)
+}
@@ -52,11 +52,21 @@ func (s *SyncMapStore) GetAll(_ context.Context) (map[any]any, error) {
 	return itemsCopy, nil
 }
 
+// Keys returns all keys in the store
+func (s *SyncMapStore) Keys(_ context.Context) ([]any, error) {
+	var keys []any
+	s.items.Range(func(key, _ any) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys, nil
+}
+
 // Register Set only when key not found
 func (s *SyncMapStore) Register(ctx context.Context, key any, value any, options ...store.Option) error {
 	_, loaded := s.items.LoadOrStore(key, value)
 	if loaded {
-		return fmt.Errorf("syncmapstore: register key %v failed: alreasy exists", key)
+		return &AlreadyExistsError{Key: key}
 	}
 	return nil
 }
@@ -92,5 +102,6 @@ func (s *SyncMapStore) Clear(_ context.Context) error {
 var (
 	_ store.StoreInterface = (*SyncMapStore)(nil)
 	_ GetAllInterface      = (*SyncMapStore)(nil)
+	_ KeysInterface        = (*SyncMapStore)(nil)
 	_ Registerable         = (*SyncMapStore)(nil)
 )
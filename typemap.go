@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/eko/gocache/lib/v4/cache"
 	"github.com/eko/gocache/lib/v4/codec"
@@ -26,26 +28,38 @@ func MustRegisterType[T any](opts ...TypeOption) {
 //   default to `cache.New[T](NewMap())`, if tag cache exists then return already eixsts error
 // - can specify T's dependencies(a slice of TypeId) with `WithDependencies`
 func RegisterType[T any](opts ...TypeOption) error {
-	typeId := TypeOf[T]()
+	return registerType(genericImpl[T](), opts...)
+}
+
+// registerType the non-generic core of `RegisterType` and `RegisterReflectType`
+func registerType(impl *typeImpl, opts ...TypeOption) error {
+	typeId := impl.typeId
 	options := NewTypeOptions(opts...)
+	if err := checkLoaders(typeId, options.Loaders); err != nil {
+		return err
+	}
 	var needSetType bool
 	typeMap := globalTypeMaps.LoadOrNew(options.TypeMapName)
-	typeMap.lock.RLock()
-	typ := typeMap.types[typeId]
-	typeMap.lock.RUnlock()
+	typeMap.lock.Lock()
+	defer typeMap.lock.Unlock()
+	typ := typeMap.load().types[typeId]
 	if typ == nil {
 		needSetType = true
 		typ = &Type{
 			typeId:         typeId,
-			new:            func() any { return New[T]() },
-			deref:          func(n any) any { p := n.(*T); return *p },
+			new:            impl.new,
+			deref:          impl.deref,
 			instancesCache: options.InstancesCache,
+			keyIndexed:     options.KeyIndex,
+			bindings:       options.Bindings,
+			preloadKeys:    options.PreloadKeys,
+			requiredKeys:   options.RequiredKeys,
 		}
 		var instance any
 		if options.UseDependencies {
 			typ.dependencies = options.Dependencies
 		} else {
-			instance = Zero[T]()
+			instance = impl.zero()
 			if dep, ok := instance.(Dependencies); ok {
 				typ.dependencies = dep.Dependencies()
 			}
@@ -54,23 +68,28 @@ func RegisterType[T any](opts ...TypeOption) error {
 			typ.description = options.Description
 		} else {
 			if instance == nil {
-				instance = Zero[T]()
+				instance = impl.zero()
 			}
 			if dep, ok := instance.(Description); ok {
 				typ.description = dep.Description()
 			}
 		}
 	} else {
+		if err := checkAlias(typeMap.load(), typeId, options.Alias); err != nil { // NOTE: validate before mutating typ
+			return err
+		}
 		typ.lock.Lock()
+		for tag := range options.InstancesCache {
+			if _, ok := typ.instancesCache[tag]; ok {
+				typ.lock.Unlock()
+				return &AlreadyExistsError{TypeId: typ.String(), Tag: tag}
+			}
+		}
 		if options.UseDependencies {
 			typ.dependencies = options.Dependencies
 			needSetType = true
 		}
 		for tag, tagCache := range options.InstancesCache {
-			if _, ok := typ.instancesCache[tag]; ok {
-				typ.lock.Unlock()
-				return fmt.Errorf("type %s tag cache %s already exists", typ.String(), tag)
-			}
 			typ.instancesCache[tag] = tagCache
 			needSetType = true
 		}
@@ -78,12 +97,23 @@ func RegisterType[T any](opts ...TypeOption) error {
 			typ.description = options.Description
 			needSetType = true
 		}
+		if options.KeyIndex && !typ.keyIndexed {
+			typ.keyIndexed = true
+			needSetType = true
+		}
+		if options.Alias != "" && options.Alias != typ.alias {
+			needSetType = true // NOTE: alias is set by `setType` after uniqueness checked
+		}
+		if len(options.PreloadKeys) > 0 {
+			typ.preloadKeys = append(append([]any{}, typ.preloadKeys...), options.PreloadKeys...)
+		}
+		if len(options.RequiredKeys) > 0 {
+			typ.requiredKeys = append(append([]any{}, typ.requiredKeys...), options.RequiredKeys...)
+		}
 		typ.lock.Unlock()
 	}
 	if needSetType {
-		typeMap.lock.Lock()
-		defer typeMap.lock.Unlock()
-		return setType[T](typeMap, typ, opts...)
+		return setType(typeMap, typ, impl, opts...)
 	}
 	return nil
 }
@@ -105,72 +135,136 @@ func SetType[T any](opts ...TypeOption) error {
 	typeMap := globalTypeMaps.LoadOrNew(options.TypeMapName)
 	typeMap.lock.Lock()
 	defer typeMap.lock.Unlock()
+	impl := genericImpl[T]()
+	if err := checkLoaders(impl.typeId, options.Loaders); err != nil {
+		return err
+	}
 	typ := &Type{
-		typeId:         TypeOf[T](),
-		new:            func() any { return New[T]() },
-		deref:          func(n any) any { p := n.(*T); return *p },
+		typeId:         impl.typeId,
+		new:            impl.new,
+		deref:          impl.deref,
 		instancesCache: options.InstancesCache,
 		dependencies:   options.Dependencies,
 		description:    options.Description,
+		keyIndexed:     options.KeyIndex,
+		bindings:       options.Bindings,
+		preloadKeys:    options.PreloadKeys,
+		requiredKeys:   options.RequiredKeys,
 	}
-	return setType[T](typeMap, typ, opts...)
+	return setType(typeMap, typ, impl, opts...)
 }
 
-func setType[T any](typeMap *TypeMap, typ *Type, opts ...TypeOption) error {
+// setType the non-generic core which completes typ with impl and stores it into typeMap, NOTE: typeMap.lock should be held
+func setType(typeMap *TypeMap, typ *Type, impl *typeImpl, opts ...TypeOption) error {
+	alias := NewTypeOptions(opts...).Alias
+	table := typeMap.load()
+	if err := checkAlias(table, typ.typeId, alias); err != nil {
+		return err
+	}
+	typeIdStr := TypeId{typ.typeId}.String()
+	if t, ok := table.strTypes[typeIdStr]; ok && t.typeId != typ.typeId {
+		return &TypeIdConflictError{TypeId: typeIdStr, Exists: t.typeId, Conflict: typ.typeId}
+	}
+	if t, ok := table.aliases[typeIdStr]; ok && t.typeId != typ.typeId { // NOTE: the type id is shadowed by alias
+		return &TypeIdConflictError{TypeId: typeIdStr, Exists: t.typeId, Conflict: typ.typeId}
+	}
 	typ.lock.Lock()
+	if alias != "" {
+		typ.alias = alias
+	}
 	if typ.instancesCache == nil {
 		typ.instancesCache = make(map[string]any)
-		typ.instancesCache[""] = NewDefaultCache[T](opts...) // NOTE: default tag is ""
+		typ.instancesCache[""] = impl.newCache(opts...) // NOTE: default tag is ""
 	}
 	for tag, tagCache := range typ.instancesCache {
 		if tagCache == nil {
-			typ.instancesCache[tag] = NewDefaultCache[T](opts...)
+			typ.instancesCache[tag] = impl.newCache(opts...)
 		}
 	}
+	typ.isCache = impl.isCache
+	typ.publish()
 	typ.lock.Unlock()
-	typeIdStr := TypeId{typ.typeId}.String()
-	if t, ok := typeMap.strTypes[typeIdStr]; ok {
-		if t.typeId != typ.typeId {
-			return fmt.Errorf("type %s and %s with same type id string", t.String(), typ.String())
-		}
+	typeMap.table.Store(table.with(typeIdStr, typ))
+	if old := table.types[typ.typeId]; old != nil && old != typ {
+		old.stopReplacedRefresh(typ)
 	}
-	typeMap.strTypes[typeIdStr] = typ
-	typeMap.types[typ.typeId] = typ
 	return nil
 }
 
-// Types returns all Types
+// checkAlias returns `*TypeIdConflictError` if alias is used by another type as alias or type id string
+func checkAlias(table *typeTable, typeId reflect.Type, alias string) error {
+	if alias == "" {
+		return nil
+	}
+	if t, ok := table.aliases[alias]; ok && t.typeId != typeId {
+		return &TypeIdConflictError{TypeId: alias, Exists: t.typeId, Conflict: typeId}
+	}
+	if t, ok := table.strTypes[alias]; ok && t.typeId != typeId {
+		return &TypeIdConflictError{TypeId: alias, Exists: t.typeId, Conflict: typeId}
+	}
+	return nil
+}
+
+// Types returns all Types, the returned map is a read-only snapshot
 func Types(opts ...TypeOption) map[reflect.Type]*Type {
-	options := NewTypeOptions(opts...)
-	typeMap := globalTypeMaps.LoadOrNew(options.TypeMapName)
-	typeMap.lock.RLock()
-	defer typeMap.lock.RUnlock()
-	return typeMap.types
+	return loadTypeMap(opts...).load().types
 }
 
 // GetType get *Type corresponding to T from global TypeMap
 func GetType[T any](opts ...TypeOption) *Type {
-	options := NewTypeOptions(opts...)
-	typeMap := globalTypeMaps.LoadOrNew(options.TypeMapName)
-	typeMap.lock.RLock()
-	defer typeMap.lock.RUnlock()
-	return typeMap.types[TypeOf[T]()]
+	return loadTypeMap(opts...).load().types[TypeOf[T]()]
 }
 
-// GetTypeByID get *Type corresponding to TypeIdStr from global TypeMap
+// GetTypeByID get *Type corresponding to TypeIdStr(or alias specified by `WithAlias`) from global TypeMap
 func GetTypeByID(typeIdStr string, opts ...TypeOption) *Type {
-	options := NewTypeOptions(opts...)
-	typeMap := globalTypeMaps.LoadOrNew(options.TypeMapName)
-	typeMap.lock.RLock()
-	defer typeMap.lock.RUnlock()
-	return typeMap.strTypes[typeIdStr]
+	table := loadTypeMap(opts...).load()
+	if typ, ok := table.strTypes[typeIdStr]; ok {
+		return typ
+	}
+	return table.aliases[typeIdStr]
+}
+
+// ResolveTypeByID like `GetTypeByID`, but if not found by the exact type id string or alias,
+// then try to resolve it by `ParseTypeId`, e.g. `[]*pkg:Foo`, `[]*pkg/path.Foo`
+func ResolveTypeByID(typeIdStr string, opts ...TypeOption) *Type {
+	if typ := GetTypeByID(typeIdStr, opts...); typ != nil {
+		return typ
+	}
+	d, err := ParseTypeId(typeIdStr)
+	if err != nil {
+		return nil
+	}
+	t, err := d.Resolve(opts...)
+	if err != nil {
+		return nil
+	}
+	return loadTypeMap(opts...).load().types[t]
+}
+
+// loadTypeMap load *TypeMap specified by opts, avoid allocating TypeOptions if no opts given
+func loadTypeMap(opts ...TypeOption) *TypeMap {
+	if len(opts) == 0 {
+		return globalTypeMaps.LoadOrNew("")
+	}
+	return globalTypeMaps.LoadOrNew(NewTypeOptions(opts...).TypeMapName)
 }
 
 type Type struct {
 	typeId         reflect.Type
 	description    string
 	dependencies   []string
-	instancesCache map[tag]any // map[tag]cache.SetterCacheInterface[T]
+	instancesCache map[tag]any  // map[tag]cache.SetterCacheInterface[T]
+	caches         atomic.Value // map[tag]any, copy-on-write snapshot of instancesCache used by lock-free reads
+	keyIndexed     bool
+	keyIndexes     map[tag]*keyIndex
+	bindings       bool // default instances cache constructs instances bound by `Bind`
+	preloadKeys    []any
+	requiredKeys   []any
+	alias          string
+	factories      map[string]factory
+	isCache        func(c any) bool // reports whether c is a cache.SetterCacheInterface[T]
+	watchers       map[uint64]watcher
+	nextWatcher    uint64
 	lock           sync.RWMutex
 	new            func() any
 	deref          func(v any) any
@@ -200,11 +294,23 @@ func (typ *Type) PkgPath() string {
 }
 
 func (typ *Type) InstancesCache(tag string) any {
+	if caches, ok := typ.caches.Load().(map[string]any); ok {
+		return caches[tag]
+	}
 	typ.lock.RLock()
 	defer typ.lock.RUnlock()
 	return typ.instancesCache[tag]
 }
 
+// publish publish a snapshot of instancesCache for lock-free reads, must be called with typ.lock held
+func (typ *Type) publish() {
+	caches := make(map[string]any, len(typ.instancesCache))
+	for tag, tagCache := range typ.instancesCache {
+		caches[tag] = tagCache
+	}
+	typ.caches.Store(caches)
+}
+
 func (typ *Type) Dependencies() []string {
 	typ.lock.RLock()
 	defer typ.lock.RUnlock()
@@ -217,6 +323,27 @@ func (typ *Type) Description() string {
 	return typ.description
 }
 
+// Alias returns the alias specified by `WithAlias`
+func (typ *Type) Alias() string {
+	typ.lock.RLock()
+	defer typ.lock.RUnlock()
+	return typ.alias
+}
+
+// PreloadKeys returns keys specified by `WithPreloadKeys`
+func (typ *Type) PreloadKeys() []any {
+	typ.lock.RLock()
+	defer typ.lock.RUnlock()
+	return typ.preloadKeys
+}
+
+// RequiredKeys returns keys specified by `WithRequiredKeys`
+func (typ *Type) RequiredKeys() []any {
+	typ.lock.RLock()
+	defer typ.lock.RUnlock()
+	return typ.requiredKeys
+}
+
 // MarshalJSON marshal Type into JSON
 func (typ *Type) MarshalJSON() ([]byte, error) {
 	var cacheInfos = make(map[string]*CacheInfo)
@@ -237,20 +364,45 @@ func (typ *Type) MarshalJSON() ([]byte, error) {
 		}
 		cacheInfos[tag] = info
 	}
+	alias := typ.alias
+	factories := typ.factoryNames()
 	typ.lock.RUnlock()
 	return json.Marshal(struct {
 		TypeId         string                `json:"type_id"`
+		Alias          string                `json:"alias,omitempty"`
 		InstancesCache map[string]*CacheInfo `json:"instances_cache,omitempty"`
+		Factories      []string              `json:"factories,omitempty"`
 		Dependencies   []string              `json:"dependencies,omitempty"`
 		Description    string                `json:"description,omitempty"`
 	}{
 		TypeId:         typ.String(),
+		Alias:          alias,
+		Factories:      factories,
 		InstancesCache: cacheInfos,
 		Dependencies:   typ.dependencies,
 		Description:    typ.description,
 	})
 }
 
+// stopReplacedRefresh stop the background refresh of typ's caches which are not reused by the replacing type
+func (typ *Type) stopReplacedRefresh(replacing *Type) {
+	reused := make(map[any]bool)
+	replacing.lock.RLock()
+	for _, c := range replacing.instancesCache {
+		if isComparableKey(c) {
+			reused[c] = true
+		}
+	}
+	replacing.lock.RUnlock()
+	typ.lock.RLock()
+	defer typ.lock.RUnlock()
+	for _, c := range typ.instancesCache {
+		if s, ok := c.(interface{ stopRefresh() }); ok && !reused[c] {
+			s.stopRefresh()
+		}
+	}
+}
+
 // CacheInfo auxiliary json serialization
 type CacheInfo struct {
 	CacheType string `json:"cache_type"`
@@ -267,13 +419,26 @@ func NewTypeOptions(opts ...TypeOption) *TypeOptions {
 
 // TypeOptions options used to control Type creation
 type TypeOptions struct {
-	TypeMapName     string
-	InstancesCache  map[tag]any
-	Dependencies    []string
-	Description     string
-	UseDependencies bool
-	UseDescription  bool
-	EnableDI        bool
+	TypeMapName      string
+	InstancesCache   map[tag]any
+	Dependencies     []string
+	Description      string
+	UseDependencies  bool
+	UseDescription   bool
+	EnableDI         bool
+	NewStore         func() store.StoreInterface
+	KeyIndex         bool
+	RefreshInterval  time.Duration
+	RefreshContext   context.Context
+	LoadTimeout      time.Duration
+	LoadRetries      int
+	LoadRetryBackoff time.Duration
+	NegativeCacheTTL time.Duration
+	Loaders          any // []cache.LoadFunction[T]
+	Bindings         bool
+	PreloadKeys      []any
+	RequiredKeys     []any
+	Alias            string
 }
 
 // Options control option func for TypeMap's type api, RegisterType|SetType
@@ -295,7 +460,7 @@ func WithInstancesCache[T any](tag string, tagCache cache.SetterCacheInterface[T
 		if tagCache != nil {
 			options.InstancesCache[tag] = tagCache
 		} else {
-			options.InstancesCache[tag] = NewDefaultCache[T](WithEnableDI(options.EnableDI))
+			options.InstancesCache[tag] = nil // NOTE: `NewDefaultCache` with all options when set type
 		}
 	}
 }
@@ -323,10 +488,121 @@ func WithEnableDI(enable bool) TypeOption {
 	}
 }
 
+// WithNewStore specify the store constructor used by default instances cache, default to `NewMap`
+func WithNewStore(newStore func() store.StoreInterface) TypeOption {
+	return func(options *TypeOptions) {
+		options.NewStore = newStore
+	}
+}
+
+// WithShardedMapStore specify default instances cache use a `ShardedMapStore` with shards
+func WithShardedMapStore(shards int) TypeOption {
+	return WithNewStore(func() store.StoreInterface {
+		return NewShardedMap(shards)
+	})
+}
+
+// WithRadixStore specify default instances cache use a `RadixStore`, which supports efficient prefix lookups
+func WithRadixStore() TypeOption {
+	return WithNewStore(func() store.StoreInterface {
+		return NewRadix()
+	})
+}
+
+// WithRefresh specify loadable instances caches(see `NewDefaultCache`) re-invoke the load function
+// for keys populated by it(not set explicitly) every interval, see `LoadableSetterCacheAny.StartRefresh`
+func WithRefresh(interval time.Duration) TypeOption {
+	return func(options *TypeOptions) {
+		options.RefreshInterval = interval
+	}
+}
+
+// WithRefreshContext specify the context used to stop the background refresh specified by `WithRefresh`
+func WithRefreshContext(ctx context.Context) TypeOption {
+	return func(options *TypeOptions) {
+		options.RefreshContext = ctx
+	}
+}
+
+// WithLoaders specify loaders of default instances cache(see `NewDefaultCache`), which are tried before the loaders
+// implemented by T, NOTE: T should be the same as the registered type, otherwise `RegisterType` returns `*TypeMismatchError`
+func WithLoaders[T any](loaders ...cache.LoadFunction[T]) TypeOption {
+	return func(options *TypeOptions) {
+		exists, _ := options.Loaders.([]cache.LoadFunction[T])
+		options.Loaders = append(append([]cache.LoadFunction[T]{}, exists...), loaders...)
+	}
+}
+
+// WithBindings specify the default instances cache(see `NewDefaultCache`) of interface T constructs the instances
+// bound by `Bind` lazily, which is specified automatically if T is registered by `Bind`
+func WithBindings() TypeOption {
+	return func(options *TypeOptions) {
+		options.Bindings = true
+	}
+}
+
+// WithPreloadKeys specify keys of instances which will be loaded by `Preload` or `PreloadAll` during boot
+func WithPreloadKeys(keys ...any) TypeOption {
+	return func(options *TypeOptions) {
+		options.PreloadKeys = append(options.PreloadKeys, keys...)
+	}
+}
+
+// WithAlias specify a short alias of the type id string, which can be used by `GetTypeByID`, *Any apis and http apis,
+// the alias should be unique in a TypeMap
+func WithAlias(alias string) TypeOption {
+	return func(options *TypeOptions) {
+		options.Alias = alias
+	}
+}
+
+// WithRequiredKeys specify keys of instances which must exist, checked by `Verify`
+func WithRequiredKeys(keys ...any) TypeOption {
+	return func(options *TypeOptions) {
+		options.RequiredKeys = append(options.RequiredKeys, keys...)
+	}
+}
+
+// WithLoadTimeout specify the timeout of each load attempt of default instances cache(see `NewDefaultCache`)
+func WithLoadTimeout(timeout time.Duration) TypeOption {
+	return func(options *TypeOptions) {
+		options.LoadTimeout = timeout
+	}
+}
+
+// WithLoadRetry specify default instances cache retry failed loads(except not found) at most retries times,
+// the wait before the nth retry is backoff*2^(n-1)
+func WithLoadRetry(retries int, backoff time.Duration) TypeOption {
+	return func(options *TypeOptions) {
+		options.LoadRetries = retries
+		options.LoadRetryBackoff = backoff
+	}
+}
+
+// WithNegativeCache specify default instances cache remember not found results of loads for ttl,
+// the load of the same key in ttl returns the not found error directly
+func WithNegativeCache(ttl time.Duration) TypeOption {
+	return func(options *TypeOptions) {
+		options.NegativeCacheTTL = ttl
+	}
+}
+
+// WithKeyIndex specify typemap maintains a key index of T's instances on Register|Set|Delete|Clear,
+// so that enumeration(`GetAll`, `Keys`, `ListAny`, ...) works for stores not implement `KeysInterface` or `GetAllInterface`
+// NOTE: instances loaded by loaders(e.g. `Loadable`) or written to the store directly will not be indexed
+func WithKeyIndex() TypeOption {
+	return func(options *TypeOptions) {
+		options.KeyIndex = true
+	}
+}
+
 // Get get instance of T from Type's instances cache
 func Get[T any](ctx context.Context, key any, opts ...Option) (T, error) {
-	options := NewOptions(opts...)
-	cache, err := getInstancesCache[T](options.Tag, false, options.TypeOptions...)
+	options := defaultOptions
+	if len(opts) > 0 {
+		options = NewOptions(opts...)
+	}
+	_, cache, err := getInstancesCache[T](options.Tag, false, options.TypeOptions...)
 	if err != nil {
 		return *new(T), err
 	}
@@ -336,7 +612,7 @@ func Get[T any](ctx context.Context, key any, opts ...Option) (T, error) {
 // GetAny get instance of T(specified by typeIdStr) from Type's instances cache
 func GetAny(ctx context.Context, typeIdStr string, key any, opts ...Option) (any, error) {
 	options := NewOptions(opts...)
-	cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
+	_, cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
 	if err != nil {
 		return nil, err
 	}
@@ -344,71 +620,52 @@ func GetAny(ctx context.Context, typeIdStr string, key any, opts ...Option) (any
 }
 
 // GetMany get multiple instances of T from Type's instances cache
+// - can fetch concurrently with `WithConcurrency`
+// - can return found values(zero for the failed) alongside a `*MultiError` with `WithPartialResults`,
+//   otherwise abort on the first error
 func GetMany[T any](ctx context.Context, keys []any, opts ...Option) ([]T, error) {
 	options := NewOptions(opts...)
-	cache, err := getInstancesCache[T](options.Tag, false, options.TypeOptions...)
+	_, cache, err := getInstancesCache[T](options.Tag, false, options.TypeOptions...)
 	if err != nil {
 		return nil, err
 	}
-	var values []T
-	for _, key := range keys {
-		value, err := cache.Get(ctx, key)
-		if err != nil {
-			return nil, err
-		}
-		values = append(values, value)
-	}
-	return values, nil
+	return getMany[T](ctx, cache.GetCodec().GetStore(), keys, cache.Get, options)
 }
 
 // GetAnyMany get multiple instances of T(specified by typeIdStr) from Type's instances cache
 func GetAnyMany(ctx context.Context, typeIdStr string, keys []any, opts ...Option) ([]any, error) {
 	options := NewOptions(opts...)
-	cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
+	_, cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
 	if err != nil {
 		return nil, err
 	}
-	var values []any
-	for _, key := range keys {
-		value, err := cache.GetAny(ctx, key)
-		if err != nil {
-			return nil, err
-		}
-		values = append(values, value)
-	}
-	return values, nil
+	return getMany[any](ctx, cache.GetCodec().GetStore(), keys, cache.GetAny, options)
 }
 
 func GetAll[T any](ctx context.Context, opts ...Option) (map[any]T, error) {
 	options := NewOptions(opts...)
-	cache, err := getInstancesCache[T](options.Tag, false, options.TypeOptions...)
+	typ, cache, err := getInstancesCache[T](options.Tag, false, options.TypeOptions...)
 	if err != nil {
 		return nil, err
 	}
-	if ga, ok := cache.GetCodec().GetStore().(GetAllInterface); ok {
-		m, err := ga.GetAll(ctx)
-		if err != nil {
-			return nil, err
-		}
-		result := make(map[any]T, len(m))
-		for k, v := range m {
-			result[k] = v.(T)
-		}
-		return result, nil
+	m, err := instanceAll(ctx, typ, options.Tag, cache)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[any]T, len(m))
+	for k, v := range m {
+		result[k] = v.(T)
 	}
-	return nil, fmt.Errorf("store %s not implement GetAllInterface", cache.GetCodec().GetStore().GetType())
+	return result, nil
 }
 
 func GetAnyAll(ctx context.Context, typeIdStr string, opts ...Option) (map[any]any, error) {
 	options := NewOptions(opts...)
-	cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
+	typ, cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
 	if err != nil {
 		return nil, err
 	}
-	if ga, ok := cache.GetCodec().GetStore().(GetAllInterface); ok {
-		return ga.GetAll(ctx)
-	}
-	return nil, fmt.Errorf("store %s not implement GetAllInterface", cache.GetCodec().GetStore().GetType())
+	return instanceAll(ctx, typ, options.Tag, cache)
 }
 
 // MustRegister register a T instance into Type's instances cache, if error then panic
@@ -423,42 +680,68 @@ func MustRegister[T any](ctx context.Context, key any, object T, opts ...Option)
 // if T not found, the default will be registered.
 func Register[T any](ctx context.Context, key any, object T, opts ...Option) error {
 	options := NewOptions(opts...)
-	cache, err := getInstancesCache[T](options.Tag, true, options.TypeOptions...)
+	typ, cache, err := getInstancesCache[T](options.Tag, true, options.TypeOptions...)
 	if err != nil {
 		return err
 	}
+	return registerInstance[T](ctx, typ, cache, options.Tag, key, object, options.StoreOptions...)
+}
+
+func registerInstance[T any](ctx context.Context, typ *Type, cache cache.SetterCacheInterface[T], tag string, key any, object T, opts ...store.Option) error {
 	if reg, ok := cache.GetCodec().GetStore().(Registerable); ok {
-		return reg.Register(ctx, key, object, options.StoreOptions...)
+		err := reg.Register(ctx, key, object, opts...)
+		if err != nil {
+			return withSubject(err, typ.String(), tag)
+		}
+		typ.notify(RegisterOperation, tag, key, object)
+		return nil
 	}
 	if _, err := cache.Get(ctx, key); err != nil { // NOTE: not atomic!
 		if !errors.Is(err, store.NotFound{}) {
 			return err
-		} else {
-			return cache.Set(ctx, key, object, options.StoreOptions...)
 		}
+		err = cache.Set(ctx, key, object, opts...)
+		if err != nil {
+			return err
+		}
+		typ.notify(RegisterOperation, tag, key, object)
+		return nil
 	}
-	return fmt.Errorf("register %s:%v failed: already exists", TypeIdOf[T]().String(), key)
+	return &AlreadyExistsError{TypeId: typ.String(), Tag: tag, Key: key}
 }
 
 // RegisterAny register a T(specified by typeIdStr) instance into Type's instances cache, if exists return error
 // if T not found, the default will be registered.
 func RegisterAny(ctx context.Context, typeIdStr string, key any, object any, opts ...Option) error {
 	options := NewOptions(opts...)
-	cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
+	typ, cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
+	if err != nil {
+		return err
+	}
+	object, err = typ.convert(options.Tag, key, object, options.Conversion)
 	if err != nil {
 		return err
 	}
 	if reg, ok := cache.GetCodec().GetStore().(Registerable); ok {
-		return reg.Register(ctx, key, object, options.StoreOptions...)
+		err = reg.Register(ctx, key, object, options.StoreOptions...)
+		if err != nil {
+			return withSubject(err, typ.String(), options.Tag)
+		}
+		typ.notify(RegisterOperation, options.Tag, key, object)
+		return nil
 	}
 	if _, err := cache.GetAny(ctx, key); err != nil { // NOTE: not atomic!
 		if !errors.Is(err, store.NotFound{}) {
 			return err
-		} else {
-			return cache.SetAny(ctx, key, object, options.StoreOptions...)
 		}
+		err = cache.SetAny(ctx, key, object, options.StoreOptions...)
+		if err != nil {
+			return err
+		}
+		typ.notify(RegisterOperation, options.Tag, key, object)
+		return nil
 	}
-	return fmt.Errorf("register any %s:%v failed: already exists", typeIdStr, key)
+	return &AlreadyExistsError{TypeId: typ.String(), Tag: options.Tag, Key: key}
 }
 
 // MustSet set a T instance into Type's instances cache, if error then panic
@@ -473,22 +756,40 @@ func MustSet[T any](ctx context.Context, key any, object T, opts ...Option) {
 // if T not found, the default will be registered.
 func Set[T any](ctx context.Context, key any, object T, opts ...Option) error { // options ...store.Option
 	options := NewOptions(opts...)
-	cache, err := getInstancesCache[T](options.Tag, true, options.TypeOptions...)
+	typ, cache, err := getInstancesCache[T](options.Tag, true, options.TypeOptions...)
 	if err != nil {
 		return err
 	}
-	return cache.Set(ctx, key, object, options.StoreOptions...)
+	return setInstance[T](ctx, typ, cache, options.Tag, key, object, options.StoreOptions...)
+}
+
+func setInstance[T any](ctx context.Context, typ *Type, cache cache.SetterCacheInterface[T], tag string, key any, object T, opts ...store.Option) error {
+	err := cache.Set(ctx, key, object, opts...)
+	if err != nil {
+		return err
+	}
+	typ.notify(SetOperation, tag, key, object)
+	return nil
 }
 
 // SetAny set a T instance(specified by typeIdStr) into Type's instances cache, if exists then override it
 // if T not found, the default will be registered.
 func SetAny(ctx context.Context, typeIdStr string, key any, object any, opts ...Option) error { // options ...store.Option
 	options := NewOptions(opts...)
-	cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
+	typ, cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
+	if err != nil {
+		return err
+	}
+	object, err = typ.convert(options.Tag, key, object, options.Conversion)
 	if err != nil {
 		return err
 	}
-	return cache.SetAny(ctx, key, object, options.StoreOptions...)
+	err = cache.SetAny(ctx, key, object, options.StoreOptions...)
+	if err != nil {
+		return err
+	}
+	typ.notify(SetOperation, options.Tag, key, object)
+	return nil
 }
 
 // MustDelete  delete a T instance specified by key, if error then panic
@@ -503,20 +804,31 @@ func MustDelete[T any](ctx context.Context, key any, opts ...Option) {
 // if T not found, the default will be registered.
 func Delete[T any](ctx context.Context, key any, opts ...Option) error {
 	options := NewOptions(opts...)
-	cache, err := getInstancesCache[T](options.Tag, true, options.TypeOptions...)
+	typ, cache, err := getInstancesCache[T](options.Tag, true, options.TypeOptions...)
+	if err != nil {
+		return err
+	}
+	return deleteInstance(ctx, typ, cache, options.Tag, key)
+}
+
+func deleteInstance(ctx context.Context, typ *Type, cache interface {
+	Delete(ctx context.Context, key any) error
+}, tag string, key any) error {
+	err := cache.Delete(ctx, key)
 	if err != nil {
 		return err
 	}
-	return cache.Delete(ctx, key)
+	typ.notify(DeleteOperation, tag, key, nil)
+	return nil
 }
 
 func DeleteAny(ctx context.Context, typeIdStr string, key any, opts ...Option) error {
 	options := NewOptions(opts...)
-	cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
+	typ, cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
 	if err != nil {
 		return err
 	}
-	return cache.Delete(ctx, key)
+	return deleteInstance(ctx, typ, cache, options.Tag, key)
 }
 
 // MustClear clear T's instances cache, if error then panic
@@ -531,20 +843,31 @@ func MustClear[T any](ctx context.Context, opts ...Option) {
 // if T not found, the default will be registered.
 func Clear[T any](ctx context.Context, opts ...Option) error {
 	options := NewOptions(opts...)
-	cache, err := getInstancesCache[T](options.Tag, true, options.TypeOptions...)
+	typ, cache, err := getInstancesCache[T](options.Tag, true, options.TypeOptions...)
 	if err != nil {
 		return err
 	}
-	return cache.Clear(ctx)
+	return clearInstances(ctx, typ, cache, options.Tag)
+}
+
+func clearInstances(ctx context.Context, typ *Type, cache interface {
+	Clear(ctx context.Context) error
+}, tag string) error {
+	err := cache.Clear(ctx)
+	if err != nil {
+		return err
+	}
+	typ.notify(ClearOperation, tag, nil, nil)
+	return nil
 }
 
 func ClearAny(ctx context.Context, typeIdStr string, opts ...Option) error {
 	options := NewOptions(opts...)
-	cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
+	typ, cache, err := getInstancesCacheAny(typeIdStr, options.Tag, options.TypeOptions...)
 	if err != nil {
 		return err
 	}
-	return cache.Clear(ctx)
+	return clearInstances(ctx, typ, cache, options.Tag)
 }
 
 func NewOptions(opts ...Option) *Options {
@@ -555,6 +878,9 @@ func NewOptions(opts ...Option) *Options {
 	return options
 }
 
+// defaultOptions used by hot paths to avoid allocating Options if no opts given, must be read-only
+var defaultOptions = &Options{}
+
 // Options control options for TypeMap's instances api, Register|Set|Get|Delete|Clear
 type Options struct {
 	// TypeOptions used to register T when register instance if T is not registered, not implement...
@@ -565,6 +891,15 @@ type Options struct {
 
 	// Tag is used to group the instances of T
 	Tag string
+
+	// Concurrency is the max number of workers used by GetMany|GetAnyMany, <= 1 means sequential
+	Concurrency int
+
+	// PartialResults used by GetMany|GetAnyMany to return found values alongside a `*MultiError`
+	PartialResults bool
+
+	// Conversion is the policy used by SetAny|RegisterAny to convert the object to T, default to `StrictConversion`
+	Conversion ConversionPolicy
 }
 
 func (options *Options) Options() []Option {
@@ -576,6 +911,15 @@ func (options *Options) Options() []Option {
 		opts = append(opts, WithStoreOption(so))
 	}
 	opts = append(opts, WithTag(options.Tag))
+	if options.Concurrency > 0 {
+		opts = append(opts, WithConcurrency(options.Concurrency))
+	}
+	if options.PartialResults {
+		opts = append(opts, WithPartialResults())
+	}
+	if options.Conversion != StrictConversion {
+		opts = append(opts, WithConversion(options.Conversion))
+	}
 	return opts
 }
 
@@ -589,6 +933,27 @@ func WithTag(tag string) Option {
 	}
 }
 
+// WithConcurrency specify the max number of workers used to fetch instances concurrently
+func WithConcurrency(concurrency int) Option {
+	return func(options *Options) {
+		options.Concurrency = concurrency
+	}
+}
+
+// WithPartialResults specify to return found values alongside a `*MultiError` which names each failing key
+func WithPartialResults() Option {
+	return func(options *Options) {
+		options.PartialResults = true
+	}
+}
+
+// WithConversion specify the policy used by SetAny|RegisterAny to convert the object to T
+func WithConversion(policy ConversionPolicy) Option {
+	return func(options *Options) {
+		options.Conversion = policy
+	}
+}
+
 // WithTypeOption specify TypeOption as Option
 func WithTypeOption(typeOption TypeOption) Option {
 	return func(options *Options) {
@@ -603,63 +968,126 @@ func WithStoreOption(storeOption store.Option) Option {
 	}
 }
 
-func getInstancesCache[T any](tag string, registerType bool, opts ...TypeOption) (cache.SetterCacheInterface[T], error) {
+func getInstancesCache[T any](tag string, registerType bool, opts ...TypeOption) (*Type, cache.SetterCacheInterface[T], error) {
 	typ := GetType[T](opts...)
 	if typ == nil {
 		if registerType {
 			err := RegisterType[T](opts...) // NOTE: register type first time if not found?
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			typ = GetType[T](opts...)
 		} else {
-			return nil, NewNotFoundError(fmt.Sprintf("type %s not found", TypeIdOf[T]().String()))
+			return nil, nil, NewNotFoundError(fmt.Sprintf("type %s not found", TypeIdOf[T]().String()))
 		}
 	}
-	typ.lock.RLock()
-	cache, ok := typ.instancesCache[tag].(cache.SetterCacheInterface[T])
-	typ.lock.RUnlock()
+	tagCache := typ.InstancesCache(tag)
+	cache, ok := tagCache.(cache.SetterCacheInterface[T])
 	if !ok {
-		return nil, fmt.Errorf("invalid type %s instances cache type: %T", typ.String(), typ.instancesCache[tag])
+		return nil, nil, &CacheTypeError{TypeId: typ.String(), Tag: tag, Cache: tagCache}
 	}
-	return cache, nil
+	return typ, cache, nil
 }
 
-func getInstancesCacheAny(typeIdStr, tag string, opts ...TypeOption) (SetterCacheAnyInterface, error) {
+func getInstancesCacheAny(typeIdStr, tag string, opts ...TypeOption) (*Type, SetterCacheAnyInterface, error) {
 	typ := GetTypeByID(typeIdStr, opts...)
 	if typ == nil {
-		return nil, NewNotFoundError(fmt.Sprintf("type %s not found", typeIdStr))
+		return nil, nil, NewNotFoundError(fmt.Sprintf("type %s not found", typeIdStr))
 	}
-	typ.lock.RLock()
-	cache, ok := typ.instancesCache[tag].(SetterCacheAnyInterface)
-	typ.lock.RUnlock()
+	tagCache := typ.InstancesCache(tag)
+	cache, ok := tagCache.(SetterCacheAnyInterface)
 	if !ok {
-		return nil, fmt.Errorf("invalid type %s instances cache type: %T", typ.String(), typ.instancesCache[tag])
+		return nil, nil, &CacheTypeError{TypeId: typ.String(), Tag: tag, Cache: tagCache}
 	}
-	return cache, nil
+	return typ, cache, nil
 }
 
 // TypeMap a map[TypeId]*Type, with type meta info and instances in *Type
 // Limitation:
 // - do not support generic apis(Register, Set, Get, ...) since `golang method must have no type parameters`,
-//   and actually no need to be public, but it is helpful to understand the data structure
+//   and actually no need to be public, but it is helpful to understand the data structure,
+//   use `Handle[T]` to get a typed `*Registry[T]` if method-based apis are preferred
 type TypeMap struct {
+	table atomic.Value // *typeTable, copy-on-write, readers never lock
+	lock  sync.Mutex   // serialize writers
+}
+
+func newTypeMap() *TypeMap {
+	tm := &TypeMap{}
+	tm.table.Store(&typeTable{
+		types:    make(map[reflect.Type]*Type),
+		strTypes: make(map[string]*Type),
+		aliases:  make(map[string]*Type),
+	})
+	return tm
+}
+
+func (tm *TypeMap) load() *typeTable {
+	return tm.table.Load().(*typeTable)
+}
+
+// typeTable immutable types table of TypeMap
+type typeTable struct {
 	types    map[reflect.Type]*Type
 	strTypes map[string]*Type
-	lock     sync.RWMutex
+	aliases  map[string]*Type
+
+	reachableOnce sync.Once
+	reachable     map[string][]reflect.Type // lazily built by `reachableTypes`
+}
+
+// with returns a copy of table with typ added, the previous alias of the same type id is replaced by typ's alias
+func (table *typeTable) with(typeIdStr string, typ *Type) *typeTable {
+	nt := &typeTable{
+		types:    make(map[reflect.Type]*Type, len(table.types)+1),
+		strTypes: make(map[string]*Type, len(table.strTypes)+1),
+		aliases:  make(map[string]*Type, len(table.aliases)+1),
+	}
+	for k, v := range table.types {
+		nt.types[k] = v
+	}
+	for k, v := range table.strTypes {
+		nt.strTypes[k] = v
+	}
+	for k, v := range table.aliases {
+		if v.typeId != typ.typeId {
+			nt.aliases[k] = v
+		}
+	}
+	nt.types[typ.typeId] = typ
+	nt.strTypes[typeIdStr] = typ
+	if typ.alias != "" {
+		nt.aliases[typ.alias] = typ
+	}
+	return nt
 }
 
 type typeMaps struct {
-	tms sync.Map // map[string]*TypeMap
+	tms  atomic.Value // map[string]*TypeMap, copy-on-write
+	lock sync.Mutex
 }
 
 // LoadOrNew load *TypeMap by typeMapName, if not found create a new *TypeMap and store it
 func (tms *typeMaps) LoadOrNew(typeMapName string) *TypeMap {
-	tm, _ := tms.tms.LoadOrStore(typeMapName, &TypeMap{
-		types:    make(map[reflect.Type]*Type),
-		strTypes: make(map[string]*Type),
-	})
-	return tm.(*TypeMap)
+	if m, ok := tms.tms.Load().(map[string]*TypeMap); ok {
+		if tm := m[typeMapName]; tm != nil {
+			return tm
+		}
+	}
+	tms.lock.Lock()
+	defer tms.lock.Unlock()
+	m, _ := tms.tms.Load().(map[string]*TypeMap)
+	if tm := m[typeMapName]; tm != nil {
+		return tm
+	}
+	nm := make(map[string]*TypeMap, len(m)+1)
+	for k, v := range m {
+		nm[k] = v
+	}
+	tm := newTypeMap()
+	nm[typeMapName] = tm
+	tms.tms.Store(nm)
+	return tm
 }
 
 var globalTypeMaps = &typeMaps{}
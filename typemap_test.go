@@ -3,7 +3,9 @@ package typemap_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/ccmonky/typemap"
@@ -454,6 +456,66 @@ func TestSetAny(t *testing.T) {
 	}
 }
 
+func TestSetAnyConversion(t *testing.T) {
+	ctx := context.Background()
+	err := typemap.RegisterType[float64]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.SetAny(ctx, "float64", "5", 5)
+	if !typemap.IsTypeMismatch(err) {
+		t.Fatalf("strict conversion should mismatch, got %v", err)
+	}
+	err = typemap.RegisterAny(ctx, "float64", "5", 5)
+	if !typemap.IsTypeMismatch(err) {
+		t.Fatalf("strict conversion should mismatch, got %v", err)
+	}
+	err = typemap.SetAny(ctx, "float64", "5", 5, typemap.WithConversion(typemap.ReflectConversion))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := typemap.Get[float64](ctx, "5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != 5.0 {
+		t.Fatalf("should == 5.0, got %v", r)
+	}
+	err = typemap.RegisterType[*ConversionTest]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeIdStr := typemap.TypeIdOf[*ConversionTest]().String()
+	value := map[string]any{"s": "json"}
+	err = typemap.SetAny(ctx, typeIdStr, "json", value, typemap.WithConversion(typemap.ReflectConversion))
+	if !typemap.IsTypeMismatch(err) {
+		t.Fatalf("reflect conversion should mismatch, got %v", err)
+	}
+	err = typemap.SetAny(ctx, typeIdStr, "json", value, typemap.WithConversion(typemap.JSONConversion))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := typemap.Get[*ConversionTest](ctx, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.S != "json" {
+		t.Fatalf("should == json, got %v", c.S)
+	}
+	err = typemap.RegisterType[string]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.SetAny(ctx, "string", "65", 65, typemap.WithConversion(typemap.ReflectConversion))
+	if !typemap.IsTypeMismatch(err) {
+		t.Fatalf("integer to string should mismatch, got %v", err)
+	}
+}
+
+type ConversionTest struct {
+	S string `json:"s"`
+}
+
 type NewTest struct {
 	S string `json:"s"`
 }
@@ -591,3 +653,133 @@ func BenchmarkRegisterTypeMultiple(b *testing.B) {
 		_ = typemap.RegisterType[uint32]()
 	}
 }
+
+type GetBench struct {
+	Value string
+}
+
+// BenchmarkGet the read path of typemap itself does not allocate, the remaining 1 alloc/op is
+// `cache.Cache.Get` of gocache boxing the string cache key into any before calling its codec
+func BenchmarkGet(b *testing.B) {
+	ctx := context.Background()
+	err := typemap.Set[*GetBench](ctx, "bench", &GetBench{Value: "bench"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_, _ = typemap.Get[*GetBench](ctx, "bench")
+	}
+}
+
+func BenchmarkGetParallel(b *testing.B) {
+	ctx := context.Background()
+	err := typemap.Set[*GetBench](ctx, "bench", &GetBench{Value: "bench"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = typemap.Get[*GetBench](ctx, "bench")
+		}
+	})
+}
+
+type ErrorsTest struct{}
+
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+	tm := typemap.WithTypeMapName("errors")
+	err := typemap.Register[*ErrorsTest](ctx, "a", &ErrorsTest{}, typemap.WithTypeOption(tm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.Register[*ErrorsTest](ctx, "a", &ErrorsTest{}, typemap.WithTypeOption(tm))
+	var ae *typemap.AlreadyExistsError
+	if !errors.As(err, &ae) {
+		t.Fatalf("should be already exists error, got %v", err)
+	}
+	if ae.TypeId != typemap.TypeIdOf[*ErrorsTest]().String() || ae.Key != "a" {
+		t.Fatalf("should fill type id and key, got %#v", ae)
+	}
+	err = typemap.RegisterType[*ErrorsTest](tm, typemap.WithInstancesCache[*ErrorsTest]("", nil))
+	if !typemap.IsAlreadyExists(err) {
+		t.Fatalf("tag cache should already exists, got %v", err)
+	}
+	err = typemap.RegisterType[*ErrorsTest](tm, typemap.WithInstancesCache[string]("str", typemap.NewDefaultCache[string]()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = typemap.Get[*ErrorsTest](ctx, "a", typemap.WithTypeOption(tm), typemap.WithTag("str"))
+	if !typemap.IsCacheType(err) {
+		t.Fatalf("should be cache type error, got %v", err)
+	}
+}
+
+type AliasTest struct {
+	S string `json:"s"`
+}
+
+type AliasConflictTest struct{}
+
+func TestAlias(t *testing.T) {
+	ctx := context.Background()
+	tm := typemap.WithTypeMapName("alias")
+	err := typemap.RegisterType[*AliasTest](tm, typemap.WithAlias("demo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ := typemap.GetTypeByID("demo", tm)
+	if typ == nil || typ.Alias() != "demo" || typ.TypeId() != typemap.TypeOf[*AliasTest]() {
+		t.Fatalf("should get type by alias, got %v", typ)
+	}
+	err = typemap.SetAny(ctx, "demo", "a", &AliasTest{S: "a"}, typemap.WithTypeOption(tm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := typemap.GetAny(ctx, "demo", "a", typemap.WithTypeOption(tm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(*AliasTest).S != "a" {
+		t.Fatal("should ==")
+	}
+	data, err := json.Marshal(typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"alias":"demo"`) {
+		t.Fatalf("should marshal alias, got %s", data)
+	}
+	err = typemap.RegisterType[*AliasConflictTest](tm, typemap.WithAlias("demo"))
+	if !typemap.IsTypeIdConflict(err) {
+		t.Fatalf("alias should be unique, got %v", err)
+	}
+	err = typemap.RegisterType[*AliasTest](tm, typemap.WithAlias("demo2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typemap.GetTypeByID("demo", tm) != nil || typemap.GetTypeByID("demo2", tm) == nil {
+		t.Fatal("alias should be replaced")
+	}
+	err = typemap.RegisterType[*AliasConflictTest](tm, typemap.WithAlias("demo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.RegisterType[*AliasConflictTest](tm, typemap.WithAlias("demo2"),
+		typemap.WithInstancesCache[*AliasConflictTest]("conflict", nil), typemap.WithDescription("conflict"))
+	if !typemap.IsTypeIdConflict(err) {
+		t.Fatalf("alias should be unique, got %v", err)
+	}
+	typ = typemap.GetType[*AliasConflictTest](tm)
+	if typ.InstancesCache("conflict") != nil || typ.Description() == "conflict" || typ.Alias() != "demo" {
+		t.Fatal("type should not be updated if alias conflicts")
+	}
+	err = typemap.RegisterType[*AliasConflictTest](tm, typemap.WithAlias(typemap.TypeIdOf[*AliasTest]().String()))
+	if !typemap.IsTypeIdConflict(err) {
+		t.Fatalf("alias should not shadow type id of other types, got %v", err)
+	}
+}
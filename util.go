@@ -2,6 +2,7 @@ package typemap
 
 import (
 	"reflect"
+	"sort"
 )
 
 // Zero create a new T's instance, and New will indirect reflect.Ptr recursively to ensure not return nil pointer
@@ -49,3 +50,32 @@ func NewConstructor[Impl, Iface any]() func() Iface {
 		return v.(Iface)
 	}
 }
+
+// sortKeys sort keys in a stable order, string keys are compared directly, others by `fmt.Sprint` and then by type
+func sortKeys(keys []any) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keyLess(keys[i], keys[j])
+	})
+}
+
+func keyLess(a, b any) bool {
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if aok && bok {
+		return as < bs
+	}
+	return sortKeyOf(a).less(sortKeyOf(b))
+}
+
+// sortedTypes returns all Types of the TypeMap specified by opts in the order of type id
+func sortedTypes(opts ...TypeOption) []*Type {
+	m := Types(opts...)
+	types := make([]*Type, 0, len(m))
+	for _, typ := range m {
+		types = append(types, typ)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].String() < types[j].String()
+	})
+	return types
+}
@@ -0,0 +1,53 @@
+package typemap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Verify check all types of the TypeMap(specified by `WithTypeMapName`), returns a `*MultiError` keyed by type id
+// which reports every problem found:
+// - instances specified by `WithRequiredKeys` can be got with the tag specified by `WithTag`
+// - dependencies refer to registered types
+// - tag caches are `cache.SetterCacheInterface[T]` of the type
+func Verify(ctx context.Context, opts ...Option) error {
+	options := NewOptions(opts...)
+	me := &MultiError{}
+	for _, typ := range sortedTypes(options.TypeOptions...) {
+		typeIdStr := typ.String()
+		for _, dep := range typ.Dependencies() {
+			if GetTypeByID(dep, options.TypeOptions...) == nil {
+				me.add(typeIdStr, fmt.Errorf("dependency %s not registered", dep))
+			}
+		}
+		typ.lock.RLock()
+		isCache := typ.isCache
+		tags := make([]string, 0, len(typ.instancesCache))
+		for tag := range typ.instancesCache {
+			tags = append(tags, tag)
+		}
+		typ.lock.RUnlock()
+		sort.Strings(tags)
+		for _, tag := range tags {
+			if tagCache := typ.InstancesCache(tag); isCache != nil && !isCache(tagCache) {
+				me.add(typeIdStr, fmt.Errorf("tag cache %s is %T, not a cache of %s", tag, tagCache, typeIdStr))
+			}
+		}
+		keys := typ.RequiredKeys()
+		if len(keys) == 0 {
+			continue
+		}
+		cache, ok := typ.InstancesCache(options.Tag).(SetterCacheAnyInterface)
+		if !ok {
+			me.add(typeIdStr, fmt.Errorf("tag cache %s not found for required keys", options.Tag))
+			continue
+		}
+		for _, key := range keys {
+			if _, err := cache.GetAny(ctx, key); err != nil {
+				me.add(typeIdStr, fmt.Errorf("required instance %v: %w", key, err))
+			}
+		}
+	}
+	return me.errorOrNil()
+}
@@ -0,0 +1,59 @@
+package typemap_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/typemap"
+)
+
+type VerifyType struct{}
+
+type VerifyOther struct{}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	tm := typemap.WithTypeMapName("verify")
+	err := typemap.RegisterType[*VerifyType](
+		tm,
+		typemap.WithRequiredKeys("a", "b"),
+		typemap.WithDependencies([]string{"missing.Type"}),
+		typemap.WithInstancesCache[*VerifyType]("", nil),
+		typemap.WithInstancesCache[*VerifyOther]("wrong", typemap.NewDefaultCache[*VerifyOther]()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.Set[*VerifyType](ctx, "a", &VerifyType{}, typemap.WithTypeOption(tm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.Verify(ctx, typemap.WithTypeOption(tm))
+	var merr *typemap.MultiError
+	if !errors.As(err, &merr) {
+		t.Fatalf("should be multi error, got %v", err)
+	}
+	if len(merr.Errors) != 3 {
+		t.Fatalf("should report 3 problems, got %v", err)
+	}
+	for _, want := range []string{"missing.Type", "tag cache wrong", "required instance b"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("should report %s, got %v", want, err)
+		}
+	}
+	ok := typemap.WithTypeMapName("verify-ok")
+	err = typemap.RegisterType[*VerifyOther](ok, typemap.WithRequiredKeys("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.Set[*VerifyOther](ctx, "a", &VerifyOther{}, typemap.WithTypeOption(ok))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = typemap.Verify(ctx, typemap.WithTypeOption(ok))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
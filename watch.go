@@ -0,0 +1,73 @@
+package typemap
+
+// Operation the instances operation which will be notified to watchers
+type Operation string
+
+var (
+	// RegisterOperation means an instance registered by `Register` or `RegisterAny`
+	RegisterOperation Operation = "register"
+
+	// SetOperation means an instance set by `Set` or `SetAny`
+	SetOperation Operation = "set"
+
+	// DeleteOperation means an instance deleted by `Delete` or `DeleteAny`
+	DeleteOperation Operation = "delete"
+
+	// ClearOperation means all instances of a tag cleared by `Clear` or `ClearAny`
+	ClearOperation Operation = "clear"
+)
+
+// Event describes a change of T's instances
+// - Key is nil for ClearOperation
+// - Value is zero for DeleteOperation and ClearOperation
+type Event[T any] struct {
+	Operation Operation
+	Tag       string
+	Key       any
+	Value     T
+}
+
+type watcher func(event Event[any])
+
+// watch add a watcher of typ's instances changes, returns a func used to remove the watcher
+func (typ *Type) watch(w watcher) func() {
+	typ.lock.Lock()
+	defer typ.lock.Unlock()
+	if typ.watchers == nil {
+		typ.watchers = make(map[uint64]watcher)
+	}
+	id := typ.nextWatcher
+	typ.nextWatcher++
+	typ.watchers[id] = w
+	return func() {
+		typ.lock.Lock()
+		defer typ.lock.Unlock()
+		delete(typ.watchers, id)
+	}
+}
+
+// notify update key index(if enabled) and call watchers synchronously, NOTE: watchers should not block
+func (typ *Type) notify(op Operation, tag string, key any, value any) {
+	if index := typ.keyIndex(tag); index != nil {
+		index.update(op, key)
+	}
+	typ.lock.RLock()
+	if len(typ.watchers) == 0 {
+		typ.lock.RUnlock()
+		return
+	}
+	watchers := make([]watcher, 0, len(typ.watchers))
+	for _, w := range typ.watchers {
+		watchers = append(watchers, w)
+	}
+	typ.lock.RUnlock()
+	event := Event[any]{
+		Operation: op,
+		Tag:       tag,
+		Key:       key,
+		Value:     value,
+	}
+	for _, w := range watchers {
+		w(event)
+	}
+}